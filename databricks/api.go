@@ -0,0 +1,23 @@
+package databricks
+
+import (
+	"encoding/json"
+	"github.com/betabandido/databricks-sdk-go/client"
+)
+
+// queryJSON sends a request to the given API path and decodes the JSON
+// response into response (unless it is nil).
+//
+// It is used by the endpoints that are not (yet) covered by the SDK.
+func queryJSON(c *client.Client, method string, path string, request interface{}, response interface{}) error {
+	bytes, err := c.Query(method, path, request)
+	if err != nil {
+		return err
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	return json.Unmarshal(bytes, response)
+}
@@ -0,0 +1,59 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+)
+
+// clusterPolicy either holds the JSON definition of the policy or the ID of
+// the policy family it is based on, along with the overrides of the latter.
+type clusterPolicy struct {
+	PolicyId                        string `json:"policy_id,omitempty"`
+	Name                            string `json:"name"`
+	Definition                      string `json:"definition,omitempty"`
+	Description                     string `json:"description,omitempty"`
+	MaxClustersPerUser              int64  `json:"max_clusters_per_user,omitempty"`
+	PolicyFamilyId                  string `json:"policy_family_id,omitempty"`
+	PolicyFamilyDefinitionOverrides string `json:"policy_family_definition_overrides,omitempty"`
+}
+
+type clusterPoliciesCreateResponse struct {
+	PolicyId string `json:"policy_id"`
+}
+
+type clusterPoliciesIdRequest struct {
+	PolicyId string `json:"policy_id"`
+}
+
+// clusterPoliciesEndpoint gives access to the Cluster Policies API, which the
+// SDK does not support.
+type clusterPoliciesEndpoint struct {
+	Client *client.Client
+}
+
+func (e *clusterPoliciesEndpoint) Create(request *clusterPolicy) (*clusterPoliciesCreateResponse, error) {
+	resp := clusterPoliciesCreateResponse{}
+	err := queryJSON(e.Client, "POST", "policies/clusters/create", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *clusterPoliciesEndpoint) Edit(request *clusterPolicy) error {
+	return queryJSON(e.Client, "POST", "policies/clusters/edit", request, nil)
+}
+
+func (e *clusterPoliciesEndpoint) Delete(request *clusterPoliciesIdRequest) error {
+	return queryJSON(e.Client, "POST", "policies/clusters/delete", request, nil)
+}
+
+func (e *clusterPoliciesEndpoint) Get(request *clusterPoliciesIdRequest) (*clusterPolicy, error) {
+	resp := clusterPolicy{}
+	err := queryJSON(e.Client, "GET", "policies/clusters/get", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
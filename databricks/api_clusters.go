@@ -0,0 +1,185 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/betabandido/databricks-sdk-go/models"
+)
+
+type clusterAzureAttributes struct {
+	FirstOnDemand   int32   `json:"first_on_demand,omitempty"`
+	Availability    string  `json:"availability,omitempty"`
+	SpotBidMaxPrice float64 `json:"spot_bid_max_price,omitempty"`
+}
+
+type clusterGcpAttributes struct {
+	GoogleServiceAccount string `json:"google_service_account,omitempty"`
+	BootDiskSize         int32  `json:"boot_disk_size,omitempty"`
+	Availability         string `json:"availability,omitempty"`
+	LocalSsdCount        int32  `json:"local_ssd_count,omitempty"`
+}
+
+type clusterDockerBasicAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+}
+
+type clusterDockerImage struct {
+	Url       string                  `json:"url"`
+	BasicAuth *clusterDockerBasicAuth `json:"basic_auth,omitempty"`
+}
+
+type clusterDbfsStorageInfo struct {
+	Destination string `json:"destination"`
+}
+
+type clusterS3StorageInfo struct {
+	Destination      string `json:"destination"`
+	Region           string `json:"region,omitempty"`
+	Endpoint         string `json:"endpoint,omitempty"`
+	EnableEncryption bool   `json:"enable_encryption,omitempty"`
+	EncryptionType   string `json:"encryption_type,omitempty"`
+	KmsKey           string `json:"kms_key,omitempty"`
+	CannedAcl        string `json:"canned_acl,omitempty"`
+}
+
+type clusterWorkspaceStorageInfo struct {
+	Destination string `json:"destination"`
+}
+
+type clusterLogConf struct {
+	Dbfs *clusterDbfsStorageInfo `json:"dbfs,omitempty"`
+	S3   *clusterS3StorageInfo   `json:"s3,omitempty"`
+}
+
+type clusterInitScriptInfo struct {
+	Dbfs      *clusterDbfsStorageInfo      `json:"dbfs,omitempty"`
+	S3        *clusterS3StorageInfo        `json:"s3,omitempty"`
+	Workspace *clusterWorkspaceStorageInfo `json:"workspace,omitempty"`
+}
+
+// clusterSpec holds the attributes that describe a cluster. It replaces
+// models.ClustersCreateRequest, which lacks some of them (e.g., init scripts).
+type clusterSpec struct {
+	ClusterName              string                        `json:"cluster_name,omitempty"`
+	SparkVersion             string                        `json:"spark_version"`
+	NodeTypeId               string                        `json:"node_type_id,omitempty"`
+	DriverNodeTypeId         string                        `json:"driver_node_type_id,omitempty"`
+	InstancePoolId           string                        `json:"instance_pool_id,omitempty"`
+	DriverInstancePoolId     string                        `json:"driver_instance_pool_id,omitempty"`
+	NumWorkers               int32                         `json:"num_workers,omitempty"`
+	Autoscale                *models.ClustersAutoScale     `json:"autoscale,omitempty"`
+	AutoterminationMinutes   int32                         `json:"autotermination_minutes,omitempty"`
+	SparkConf                map[string]string             `json:"spark_conf,omitempty"`
+	SparkEnvVars             map[string]string             `json:"spark_env_vars,omitempty"`
+	CustomTags               map[string]string             `json:"custom_tags,omitempty"`
+	SshPublicKeys            []string                      `json:"ssh_public_keys,omitempty"`
+	EnableElasticDisk        bool                          `json:"enable_elastic_disk,omitempty"`
+	AwsAttributes            *models.ClustersAwsAttributes `json:"aws_attributes,omitempty"`
+	AzureAttributes          *clusterAzureAttributes       `json:"azure_attributes,omitempty"`
+	GcpAttributes            *clusterGcpAttributes         `json:"gcp_attributes,omitempty"`
+	ClusterLogConf           *clusterLogConf               `json:"cluster_log_conf,omitempty"`
+	InitScripts              []clusterInitScriptInfo       `json:"init_scripts,omitempty"`
+	DockerImage              *clusterDockerImage           `json:"docker_image,omitempty"`
+	DataSecurityMode         string                        `json:"data_security_mode,omitempty"`
+	SingleUserName           string                        `json:"single_user_name,omitempty"`
+	RuntimeEngine            string                        `json:"runtime_engine,omitempty"`
+	PolicyId                 string                        `json:"policy_id,omitempty"`
+	ApplyPolicyDefaultValues bool                          `json:"apply_policy_default_values,omitempty"`
+}
+
+// clustersCreateRequest adds the idempotency token to the spec of the cluster.
+// Creating a cluster with the token of an existing one returns the ID of the
+// latter, instead of creating a new cluster.
+type clustersCreateRequest struct {
+	clusterSpec
+	IdempotencyToken string `json:"idempotency_token,omitempty"`
+}
+
+type clustersCreateResponse struct {
+	ClusterId string `json:"cluster_id"`
+}
+
+type clustersEditRequest struct {
+	ClusterId string `json:"cluster_id"`
+	clusterSpec
+}
+
+// clustersResizeRequest sets either the number of workers (which can be zero)
+// or the autoscale range of a cluster.
+type clustersResizeRequest struct {
+	ClusterId  string                    `json:"cluster_id"`
+	NumWorkers *int32                    `json:"num_workers,omitempty"`
+	Autoscale  *models.ClustersAutoScale `json:"autoscale,omitempty"`
+}
+
+// clustersIdRequest is used by the operations that only take the ID of the
+// cluster (e.g., get, start or delete).
+type clustersIdRequest struct {
+	ClusterId string `json:"cluster_id"`
+}
+
+type clusterInfo struct {
+	ClusterId string `json:"cluster_id"`
+	clusterSpec
+	State             models.ClustersClusterState       `json:"state"`
+	StateMessage      string                            `json:"state_message,omitempty"`
+	DefaultTags       map[string]string                 `json:"default_tags,omitempty"`
+	TerminationReason *models.ClustersTerminationReason `json:"termination_reason,omitempty"`
+	Driver            *models.ClustersSparkNode         `json:"driver,omitempty"`
+	JdbcPort          int32                             `json:"jdbc_port,omitempty"`
+	SparkContextId    int64                             `json:"spark_context_id,omitempty"`
+	CreatorUserName   string                            `json:"creator_user_name,omitempty"`
+	ClusterMemoryMb   int64                             `json:"cluster_memory_mb,omitempty"`
+	ClusterCores      float32                           `json:"cluster_cores,omitempty"`
+	StartTime         int64                             `json:"start_time,omitempty"`
+}
+
+// clustersEndpoint gives access to the Clusters API using clusterSpec, so
+// that all the attributes of a cluster are sent and read back.
+type clustersEndpoint struct {
+	Client *client.Client
+}
+
+func (e *clustersEndpoint) Create(request *clustersCreateRequest) (*clustersCreateResponse, error) {
+	resp := clustersCreateResponse{}
+	err := queryJSON(e.Client, "POST", "clusters/create", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *clustersEndpoint) Edit(request *clustersEditRequest) error {
+	return queryJSON(e.Client, "POST", "clusters/edit", request, nil)
+}
+
+func (e *clustersEndpoint) Resize(request *clustersResizeRequest) error {
+	return queryJSON(e.Client, "POST", "clusters/resize", request, nil)
+}
+
+func (e *clustersEndpoint) Start(request *clustersIdRequest) error {
+	return queryJSON(e.Client, "POST", "clusters/start", request, nil)
+}
+
+func (e *clustersEndpoint) Restart(request *clustersIdRequest) error {
+	return queryJSON(e.Client, "POST", "clusters/restart", request, nil)
+}
+
+func (e *clustersEndpoint) Delete(request *clustersIdRequest) error {
+	return queryJSON(e.Client, "POST", "clusters/delete", request, nil)
+}
+
+func (e *clustersEndpoint) PermanentDelete(request *clustersIdRequest) error {
+	return queryJSON(e.Client, "POST", "clusters/permanent-delete", request, nil)
+}
+
+func (e *clustersEndpoint) Get(request *clustersIdRequest) (*clusterInfo, error) {
+	resp := clusterInfo{}
+	err := queryJSON(e.Client, "GET", "clusters/get", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,133 @@
+package databricks
+
+import (
+	"encoding/base64"
+	"github.com/betabandido/databricks-sdk-go/client"
+	"io"
+)
+
+// dbfsMaxBlockSize is the largest amount of data that can be sent in a
+// single add-block request.
+const dbfsMaxBlockSize = 1 << 20
+
+type dbfsCreateRequest struct {
+	Path      string `json:"path"`
+	Overwrite bool   `json:"overwrite,omitempty"`
+}
+
+type dbfsCreateResponse struct {
+	Handle int64 `json:"handle"`
+}
+
+type dbfsAddBlockRequest struct {
+	Handle int64  `json:"handle"`
+	Data   string `json:"data"`
+}
+
+type dbfsCloseRequest struct {
+	Handle int64 `json:"handle"`
+}
+
+type dbfsGetStatusRequest struct {
+	Path string `json:"path"`
+}
+
+type dbfsFileInfo struct {
+	Path     string `json:"path"`
+	IsDir    bool   `json:"is_dir"`
+	FileSize int64  `json:"file_size"`
+}
+
+type dbfsDeleteRequest struct {
+	Path      string `json:"path"`
+	Recursive bool   `json:"recursive,omitempty"`
+}
+
+// dbfsEndpoint gives access to the DBFS API, which the SDK does not support.
+type dbfsEndpoint struct {
+	Client *client.Client
+}
+
+func (e *dbfsEndpoint) Create(request *dbfsCreateRequest) (*dbfsCreateResponse, error) {
+	resp := dbfsCreateResponse{}
+	err := queryJSON(e.Client, "POST", "dbfs/create", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *dbfsEndpoint) AddBlock(request *dbfsAddBlockRequest) error {
+	return queryJSON(e.Client, "POST", "dbfs/add-block", request, nil)
+}
+
+func (e *dbfsEndpoint) Close(request *dbfsCloseRequest) error {
+	return queryJSON(e.Client, "POST", "dbfs/close", request, nil)
+}
+
+func (e *dbfsEndpoint) GetStatus(request *dbfsGetStatusRequest) (*dbfsFileInfo, error) {
+	resp := dbfsFileInfo{}
+	err := queryJSON(e.Client, "GET", "dbfs/get-status", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *dbfsEndpoint) Delete(request *dbfsDeleteRequest) error {
+	return queryJSON(e.Client, "POST", "dbfs/delete", request, nil)
+}
+
+// Upload streams the contents of reader into the file at the given path,
+// sending them in blocks no larger than the API allows. If the upload fails,
+// the partial file is deleted, so that it does not block later uploads.
+func (e *dbfsEndpoint) Upload(path string, reader io.Reader, overwrite bool) error {
+	resp, err := e.Create(&dbfsCreateRequest{
+		Path:      path,
+		Overwrite: overwrite,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = e.addBlocks(resp.Handle, reader)
+	if err != nil {
+		// The original error is more relevant than the ones of the cleanup.
+		e.Close(&dbfsCloseRequest{
+			Handle: resp.Handle,
+		})
+		e.Delete(&dbfsDeleteRequest{
+			Path: path,
+		})
+		return err
+	}
+
+	return e.Close(&dbfsCloseRequest{
+		Handle: resp.Handle,
+	})
+}
+
+func (e *dbfsEndpoint) addBlocks(handle int64, reader io.Reader) error {
+	buffer := make([]byte, dbfsMaxBlockSize)
+	for {
+		n, err := io.ReadFull(reader, buffer)
+		if n > 0 {
+			blockErr := e.AddBlock(&dbfsAddBlockRequest{
+				Handle: handle,
+				Data:   base64.StdEncoding.EncodeToString(buffer[:n]),
+			})
+			if blockErr != nil {
+				return blockErr
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
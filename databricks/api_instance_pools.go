@@ -0,0 +1,103 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+)
+
+type instancePoolAwsAttributes struct {
+	Availability        string `json:"availability,omitempty"`
+	ZoneId              string `json:"zone_id,omitempty"`
+	SpotBidPricePercent int32  `json:"spot_bid_price_percent,omitempty"`
+}
+
+type instancePoolAzureAttributes struct {
+	Availability    string  `json:"availability,omitempty"`
+	SpotBidMaxPrice float64 `json:"spot_bid_max_price,omitempty"`
+}
+
+type instancePoolDiskType struct {
+	EbsVolumeType       string `json:"ebs_volume_type,omitempty"`
+	AzureDiskVolumeType string `json:"azure_disk_volume_type,omitempty"`
+}
+
+type instancePoolDiskSpec struct {
+	DiskType  *instancePoolDiskType `json:"disk_type,omitempty"`
+	DiskCount int32                 `json:"disk_count,omitempty"`
+	DiskSize  int32                 `json:"disk_size,omitempty"`
+}
+
+// instancePoolSpec holds the attributes that describe an instance pool.
+type instancePoolSpec struct {
+	InstancePoolName                   string                       `json:"instance_pool_name"`
+	MinIdleInstances                   int32                        `json:"min_idle_instances,omitempty"`
+	MaxCapacity                        int32                        `json:"max_capacity,omitempty"`
+	NodeTypeId                         string                       `json:"node_type_id"`
+	IdleInstanceAutoterminationMinutes int32                        `json:"idle_instance_autotermination_minutes"`
+	EnableElasticDisk                  bool                         `json:"enable_elastic_disk,omitempty"`
+	PreloadedSparkVersions             []string                     `json:"preloaded_spark_versions,omitempty"`
+	DiskSpec                           *instancePoolDiskSpec        `json:"disk_spec,omitempty"`
+	AwsAttributes                      *instancePoolAwsAttributes   `json:"aws_attributes,omitempty"`
+	AzureAttributes                    *instancePoolAzureAttributes `json:"azure_attributes,omitempty"`
+	CustomTags                         map[string]string            `json:"custom_tags,omitempty"`
+}
+
+type instancePoolsCreateResponse struct {
+	InstancePoolId string `json:"instance_pool_id"`
+}
+
+// instancePoolsEditRequest only has the attributes that can be edited, along
+// with the node type (which the API requires, even if it cannot change).
+type instancePoolsEditRequest struct {
+	InstancePoolId                     string            `json:"instance_pool_id"`
+	InstancePoolName                   string            `json:"instance_pool_name"`
+	MinIdleInstances                   int32             `json:"min_idle_instances,omitempty"`
+	MaxCapacity                        int32             `json:"max_capacity,omitempty"`
+	NodeTypeId                         string            `json:"node_type_id"`
+	IdleInstanceAutoterminationMinutes int32             `json:"idle_instance_autotermination_minutes"`
+	CustomTags                         map[string]string `json:"custom_tags,omitempty"`
+}
+
+type instancePoolsIdRequest struct {
+	InstancePoolId string `json:"instance_pool_id"`
+}
+
+type instancePoolInfo struct {
+	InstancePoolId string `json:"instance_pool_id"`
+	instancePoolSpec
+	State       string            `json:"state,omitempty"`
+	DefaultTags map[string]string `json:"default_tags,omitempty"`
+}
+
+// instancePoolsEndpoint gives access to the Instance Pools API, which the SDK
+// does not support.
+type instancePoolsEndpoint struct {
+	Client *client.Client
+}
+
+func (e *instancePoolsEndpoint) Create(request *instancePoolSpec) (*instancePoolsCreateResponse, error) {
+	resp := instancePoolsCreateResponse{}
+	err := queryJSON(e.Client, "POST", "instance-pools/create", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *instancePoolsEndpoint) Edit(request *instancePoolsEditRequest) error {
+	return queryJSON(e.Client, "POST", "instance-pools/edit", request, nil)
+}
+
+func (e *instancePoolsEndpoint) Delete(request *instancePoolsIdRequest) error {
+	return queryJSON(e.Client, "POST", "instance-pools/delete", request, nil)
+}
+
+func (e *instancePoolsEndpoint) Get(request *instancePoolsIdRequest) (*instancePoolInfo, error) {
+	resp := instancePoolInfo{}
+	err := queryJSON(e.Client, "GET", "instance-pools/get", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,157 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+)
+
+type jobNotebookTask struct {
+	NotebookPath   string            `json:"notebook_path"`
+	BaseParameters map[string]string `json:"base_parameters,omitempty"`
+}
+
+type jobSparkJarTask struct {
+	JarUri        string   `json:"jar_uri,omitempty"`
+	MainClassName string   `json:"main_class_name"`
+	Parameters    []string `json:"parameters,omitempty"`
+}
+
+type jobSparkPythonTask struct {
+	PythonFile string   `json:"python_file"`
+	Parameters []string `json:"parameters,omitempty"`
+}
+
+type jobSparkSubmitTask struct {
+	Parameters []string `json:"parameters,omitempty"`
+}
+
+type jobEmailNotifications struct {
+	OnStart               []string `json:"on_start,omitempty"`
+	OnSuccess             []string `json:"on_success,omitempty"`
+	OnFailure             []string `json:"on_failure,omitempty"`
+	NoAlertForSkippedRuns bool     `json:"no_alert_for_skipped_runs,omitempty"`
+}
+
+type jobCronSchedule struct {
+	QuartzCronExpression string `json:"quartz_cron_expression"`
+	TimezoneId           string `json:"timezone_id"`
+	PauseStatus          string `json:"pause_status,omitempty"`
+}
+
+// jobTaskSettings holds the attributes shared by single-task jobs and by each
+// of the tasks of a multi-task job.
+type jobTaskSettings struct {
+	ExistingClusterId      string                 `json:"existing_cluster_id,omitempty"`
+	NewCluster             *clusterSpec           `json:"new_cluster,omitempty"`
+	NotebookTask           *jobNotebookTask       `json:"notebook_task,omitempty"`
+	SparkJarTask           *jobSparkJarTask       `json:"spark_jar_task,omitempty"`
+	SparkPythonTask        *jobSparkPythonTask    `json:"spark_python_task,omitempty"`
+	SparkSubmitTask        *jobSparkSubmitTask    `json:"spark_submit_task,omitempty"`
+	EmailNotifications     *jobEmailNotifications `json:"email_notifications,omitempty"`
+	TimeoutSeconds         int32                  `json:"timeout_seconds,omitempty"`
+	MaxRetries             int32                  `json:"max_retries,omitempty"`
+	MinRetryIntervalMillis int32                  `json:"min_retry_interval_millis,omitempty"`
+	RetryOnTimeout         bool                   `json:"retry_on_timeout,omitempty"`
+}
+
+type jobTaskDependency struct {
+	TaskKey string `json:"task_key"`
+}
+
+type jobTask struct {
+	TaskKey       string              `json:"task_key"`
+	Description   string              `json:"description,omitempty"`
+	DependsOn     []jobTaskDependency `json:"depends_on,omitempty"`
+	JobClusterKey string              `json:"job_cluster_key,omitempty"`
+	jobTaskSettings
+}
+
+type jobCluster struct {
+	JobClusterKey string       `json:"job_cluster_key"`
+	NewCluster    *clusterSpec `json:"new_cluster"`
+}
+
+type jobSettings struct {
+	Name string `json:"name,omitempty"`
+	jobTaskSettings
+	Schedule          *jobCronSchedule `json:"schedule,omitempty"`
+	MaxConcurrentRuns int32            `json:"max_concurrent_runs,omitempty"`
+	Tasks             []jobTask        `json:"tasks,omitempty"`
+	JobClusters       []jobCluster     `json:"job_clusters,omitempty"`
+	Format            string           `json:"format,omitempty"`
+}
+
+func (s *jobSettings) isMultiTask() bool {
+	return len(s.Tasks) > 0 || s.Format == jobFormatMultiTask
+}
+
+type jobsCreateResponse struct {
+	JobId int64 `json:"job_id"`
+}
+
+type jobsGetRequest struct {
+	JobId int64 `json:"job_id"`
+
+	// MultiTask selects the version of the API that returns the tasks of
+	// multi-task jobs.
+	MultiTask bool `json:"-"`
+}
+
+type jobsGetResponse struct {
+	JobId           int64        `json:"job_id"`
+	CreatorUserName string       `json:"creator_user_name,omitempty"`
+	Settings        *jobSettings `json:"settings,omitempty"`
+	CreatedTime     int64        `json:"created_time,omitempty"`
+}
+
+type jobsResetRequest struct {
+	JobId       int64        `json:"job_id"`
+	NewSettings *jobSettings `json:"new_settings"`
+}
+
+type jobsDeleteRequest struct {
+	JobId int64 `json:"job_id"`
+}
+
+const jobFormatMultiTask = "MULTI_TASK"
+
+// jobsEndpoint gives access to the Jobs API, which the SDK does not support.
+type jobsEndpoint struct {
+	Client *client.Client
+}
+
+// jobsPath returns the path of a Jobs API method. Multi-task jobs are only
+// supported by version 2.1 of the API, whereas the client is bound to 2.0.
+func jobsPath(multiTask bool, method string) string {
+	if multiTask {
+		return "../2.1/jobs/" + method
+	}
+	return "jobs/" + method
+}
+
+func (e *jobsEndpoint) Create(request *jobSettings) (*jobsCreateResponse, error) {
+	resp := jobsCreateResponse{}
+	err := queryJSON(e.Client, "POST", jobsPath(request.isMultiTask(), "create"), request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *jobsEndpoint) Get(request *jobsGetRequest) (*jobsGetResponse, error) {
+	resp := jobsGetResponse{}
+	err := queryJSON(e.Client, "GET", jobsPath(request.MultiTask, "get"), request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *jobsEndpoint) Reset(request *jobsResetRequest) error {
+	return queryJSON(e.Client, "POST", jobsPath(request.NewSettings.isMultiTask(), "reset"), request, nil)
+}
+
+func (e *jobsEndpoint) Delete(request *jobsDeleteRequest) error {
+	return queryJSON(e.Client, "POST", "jobs/delete", request, nil)
+}
@@ -0,0 +1,76 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+)
+
+type libraryPythonPyPi struct {
+	Package string `json:"package"`
+	Repo    string `json:"repo,omitempty"`
+}
+
+type libraryMaven struct {
+	Coordinates string   `json:"coordinates"`
+	Repo        string   `json:"repo,omitempty"`
+	Exclusions  []string `json:"exclusions,omitempty"`
+}
+
+type libraryRCran struct {
+	Package string `json:"package"`
+	Repo    string `json:"repo,omitempty"`
+}
+
+type library struct {
+	Jar   string             `json:"jar,omitempty"`
+	Egg   string             `json:"egg,omitempty"`
+	Whl   string             `json:"whl,omitempty"`
+	Pypi  *libraryPythonPyPi `json:"pypi,omitempty"`
+	Maven *libraryMaven      `json:"maven,omitempty"`
+	Cran  *libraryRCran      `json:"cran,omitempty"`
+}
+
+type libraryFullStatus struct {
+	Library                 *library `json:"library"`
+	Status                  string   `json:"status"`
+	Messages                []string `json:"messages,omitempty"`
+	IsLibraryForAllClusters bool     `json:"is_library_for_all_clusters,omitempty"`
+}
+
+// librariesRequest is used to both install and uninstall libraries.
+type librariesRequest struct {
+	ClusterId string    `json:"cluster_id"`
+	Libraries []library `json:"libraries"`
+}
+
+type librariesClusterStatusRequest struct {
+	ClusterId string `json:"cluster_id"`
+}
+
+type librariesClusterStatusResponse struct {
+	ClusterId       string              `json:"cluster_id"`
+	LibraryStatuses []libraryFullStatus `json:"library_statuses,omitempty"`
+}
+
+// librariesEndpoint gives access to the Libraries API, which the SDK does not
+// support.
+type librariesEndpoint struct {
+	Client *client.Client
+}
+
+func (e *librariesEndpoint) Install(request *librariesRequest) error {
+	return queryJSON(e.Client, "POST", "libraries/install", request, nil)
+}
+
+func (e *librariesEndpoint) Uninstall(request *librariesRequest) error {
+	return queryJSON(e.Client, "POST", "libraries/uninstall", request, nil)
+}
+
+func (e *librariesEndpoint) ClusterStatus(request *librariesClusterStatusRequest) (*librariesClusterStatusResponse, error) {
+	resp := librariesClusterStatusResponse{}
+	err := queryJSON(e.Client, "GET", "libraries/cluster-status", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,123 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+)
+
+type secretScope struct {
+	Name        string `json:"name"`
+	BackendType string `json:"backend_type,omitempty"`
+}
+
+type secretsCreateScopeRequest struct {
+	Scope                  string `json:"scope"`
+	InitialManagePrincipal string `json:"initial_manage_principal,omitempty"`
+}
+
+type secretsDeleteScopeRequest struct {
+	Scope string `json:"scope"`
+}
+
+type secretsListScopesResponse struct {
+	Scopes []secretScope `json:"scopes,omitempty"`
+}
+
+type secretMetadata struct {
+	Key                  string `json:"key"`
+	LastUpdatedTimestamp int64  `json:"last_updated_timestamp,omitempty"`
+}
+
+type secretsPutRequest struct {
+	Scope       string `json:"scope"`
+	Key         string `json:"key"`
+	StringValue string `json:"string_value"`
+}
+
+type secretsDeleteRequest struct {
+	Scope string `json:"scope"`
+	Key   string `json:"key"`
+}
+
+type secretsListRequest struct {
+	Scope string `json:"scope"`
+}
+
+type secretsListResponse struct {
+	Secrets []secretMetadata `json:"secrets,omitempty"`
+}
+
+type secretAcl struct {
+	Principal  string `json:"principal"`
+	Permission string `json:"permission"`
+}
+
+type secretsPutAclRequest struct {
+	Scope      string `json:"scope"`
+	Principal  string `json:"principal"`
+	Permission string `json:"permission"`
+}
+
+type secretsAclRequest struct {
+	Scope     string `json:"scope"`
+	Principal string `json:"principal"`
+}
+
+// secretsEndpoint gives access to the Secrets API, which the SDK does not
+// support.
+type secretsEndpoint struct {
+	Client *client.Client
+}
+
+func (e *secretsEndpoint) CreateScope(request *secretsCreateScopeRequest) error {
+	return queryJSON(e.Client, "POST", "secrets/scopes/create", request, nil)
+}
+
+func (e *secretsEndpoint) DeleteScope(request *secretsDeleteScopeRequest) error {
+	return queryJSON(e.Client, "POST", "secrets/scopes/delete", request, nil)
+}
+
+func (e *secretsEndpoint) ListScopes() (*secretsListScopesResponse, error) {
+	resp := secretsListScopesResponse{}
+	err := queryJSON(e.Client, "GET", "secrets/scopes/list", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *secretsEndpoint) Put(request *secretsPutRequest) error {
+	return queryJSON(e.Client, "POST", "secrets/put", request, nil)
+}
+
+func (e *secretsEndpoint) Delete(request *secretsDeleteRequest) error {
+	return queryJSON(e.Client, "POST", "secrets/delete", request, nil)
+}
+
+func (e *secretsEndpoint) List(request *secretsListRequest) (*secretsListResponse, error) {
+	resp := secretsListResponse{}
+	err := queryJSON(e.Client, "GET", "secrets/list", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (e *secretsEndpoint) PutAcl(request *secretsPutAclRequest) error {
+	return queryJSON(e.Client, "POST", "secrets/acls/put", request, nil)
+}
+
+func (e *secretsEndpoint) DeleteAcl(request *secretsAclRequest) error {
+	return queryJSON(e.Client, "POST", "secrets/acls/delete", request, nil)
+}
+
+func (e *secretsEndpoint) GetAcl(request *secretsAclRequest) (*secretAcl, error) {
+	resp := secretAcl{}
+	err := queryJSON(e.Client, "GET", "secrets/acls/get", request, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
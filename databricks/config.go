@@ -1,9 +1,9 @@
 package databricks
 
 import (
-	"github.com/betabandido/databricks-sdk-go/api/clusters"
 	"github.com/betabandido/databricks-sdk-go/api/workspace"
 	apiClient "github.com/betabandido/databricks-sdk-go/client"
+	"os"
 	"time"
 )
 
@@ -18,8 +18,15 @@ type Config struct {
 }
 
 type Client struct {
-	clusters  *clusters.Endpoint
-	workspace *workspace.Endpoint
+	domain          string
+	clusters        *clustersEndpoint
+	workspace       *workspace.Endpoint
+	jobs            *jobsEndpoint
+	dbfs            *dbfsEndpoint
+	secrets         *secretsEndpoint
+	libraries       *librariesEndpoint
+	clusterPolicies *clusterPoliciesEndpoint
+	instancePools   *instancePoolsEndpoint
 }
 
 func (c *Config) Client() (interface{}, error) {
@@ -36,8 +43,22 @@ func (c *Config) Client() (interface{}, error) {
 		return nil, err
 	}
 
-	client.clusters = &clusters.Endpoint{Client: cl}
+	// The SDK falls back to the environment when no domain is given, and so
+	// does the provider when building the URLs of the resources.
+	if c.Domain != nil {
+		client.domain = *c.Domain
+	} else {
+		client.domain = os.Getenv("DATABRICKS_DOMAIN")
+	}
+
+	client.clusters = &clustersEndpoint{Client: cl}
 	client.workspace = &workspace.Endpoint{Client: cl}
+	client.jobs = &jobsEndpoint{Client: cl}
+	client.dbfs = &dbfsEndpoint{Client: cl}
+	client.secrets = &secretsEndpoint{Client: cl}
+	client.libraries = &librariesEndpoint{Client: cl}
+	client.clusterPolicies = &clusterPoliciesEndpoint{Client: cl}
+	client.instancePools = &instancePoolsEndpoint{Client: cl}
 
 	return &client, nil
 }
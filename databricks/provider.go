@@ -17,8 +17,16 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"databricks_cluster":  resourceDatabricksCluster(),
-			"databricks_notebook": resourceDatabricksNotebook(),
+			"databricks_cluster":        resourceDatabricksCluster(),
+			"databricks_cluster_policy": resourceDatabricksClusterPolicy(),
+			"databricks_dbfs_file":      resourceDatabricksDbfsFile(),
+			"databricks_instance_pool":  resourceDatabricksInstancePool(),
+			"databricks_job":            resourceDatabricksJob(),
+			"databricks_library":        resourceDatabricksLibrary(),
+			"databricks_notebook":       resourceDatabricksNotebook(),
+			"databricks_secret":         resourceDatabricksSecret(),
+			"databricks_secret_acl":     resourceDatabricksSecretAcl(),
+			"databricks_secret_scope":   resourceDatabricksSecretScope(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
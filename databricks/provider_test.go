@@ -1,9 +1,15 @@
 package databricks
 
 import (
+	"encoding/json"
+	apiClient "github.com/betabandido/databricks-sdk-go/client"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -40,3 +46,61 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("DATABRICKS_WORKSPACE must be set for acceptance tests")
 	}
 }
+
+// testDatabricksHandler handles a request to the API path (e.g.,
+// "clusters/get") with the given body, and returns the object to send back.
+type testDatabricksHandler func(path string, body []byte) interface{}
+
+// testDatabricksErrorResponse makes the test server fail a request with the
+// given status code.
+type testDatabricksErrorResponse struct {
+	StatusCode int    `json:"-"`
+	ErrorCode  string `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+// testDatabricksServer starts a server standing in for the Databricks API,
+// and returns a client connected to it along with a function to stop it.
+func testDatabricksServer(t *testing.T, handler testDatabricksHandler) (*apiClient.Client, func()) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		}
+
+		resp := handler(strings.TrimPrefix(r.URL.Path, "/api/2.0/"), body)
+		if resp == nil {
+			resp = struct{}{}
+		}
+
+		if errorResp, ok := resp.(testDatabricksErrorResponse); ok {
+			w.WriteHeader(errorResp.StatusCode)
+		}
+
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	// The SDK client uses the default transport, which needs to trust the
+	// certificate of the test server.
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = server.Client().Transport
+
+	domain := server.Listener.Addr().String()
+	token := "token"
+
+	// Temporary errors are retried as by the provider's client, but without
+	// waiting.
+	client, err := apiClient.NewClient(apiClient.Options{
+		Domain:     &domain,
+		Token:      &token,
+		MaxRetries: 1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	return client, func() {
+		http.DefaultTransport = defaultTransport
+		server.Close()
+	}
+}
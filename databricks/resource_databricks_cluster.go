@@ -1,13 +1,34 @@
 package databricks
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/betabandido/databricks-sdk-go/client"
 	"github.com/betabandido/databricks-sdk-go/models"
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 	"log"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// clusterMinPollInterval is the shortest time to wait between two checks of
+// the state of a cluster.
+const clusterMinPollInterval = 2 * time.Second
+
+// clusterUnchangedChecks is the number of polls in a row a cluster has to be
+// seen running after a resize or edit that it was not seen going through.
+const clusterUnchangedChecks = 3
+
+// clusterStateUnchanged stands for the state of a cluster that was resized or
+// edited but has not been seen changing yet.
+const clusterStateUnchanged models.ClustersClusterState = "UNCHANGED"
+
 func resourceDatabricksCluster() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDatabricksClusterCreate,
@@ -15,136 +36,721 @@ func resourceDatabricksCluster() *schema.Resource {
 		Update: resourceDatabricksClusterUpdate,
 		Delete: resourceDatabricksClusterDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: resourceDatabricksClusterImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		CustomizeDiff: resourceDatabricksClusterCustomizeDiff,
+
+		SchemaVersion: 1,
+		MigrateState:  resourceDatabricksClusterMigrateState,
+
+		Schema: resourceDatabricksClusterSchema(),
+	}
+}
+
+// resourceDatabricksClusterAwsAttributesSetKey matches the keys of the
+// aws_attributes of a cluster spec while it was a set, where the index is the
+// hash of the element.
+var resourceDatabricksClusterAwsAttributesSetKey = regexp.MustCompile(`(^|\.)aws_attributes\.\d+\.`)
+
+// resourceDatabricksClusterMigrateState migrates the states of resources with
+// a cluster spec (i.e., clusters and jobs). Version 1 turned aws_attributes
+// from a set into a list.
+func resourceDatabricksClusterMigrateState(
+	v int,
+	is *terraform.InstanceState,
+	meta interface{},
+) (*terraform.InstanceState, error) {
+	if is.Empty() {
+		return is, nil
+	}
+
+	switch v {
+	case 0:
+		log.Printf("[INFO] Migrating aws_attributes of %s from a set to a list", is.ID)
+
+		attributes := make(map[string]string, len(is.Attributes))
+		for k, value := range is.Attributes {
+			k = resourceDatabricksClusterAwsAttributesSetKey.ReplaceAllString(k, "${1}aws_attributes.0.")
+			attributes[k] = value
+		}
+		is.Attributes = attributes
+
+		return is, nil
+	default:
+		return is, fmt.Errorf("unexpected schema version: %d", v)
+	}
+}
+
+func resourceDatabricksClusterSchema() map[string]*schema.Schema {
+	s := resourceDatabricksClusterSpecSchema()
+
+	s["num_workers"].ConflictsWith = []string{"autoscale"}
+	s["autoscale"].ConflictsWith = []string{"num_workers"}
+
+	s["aws_attributes"].ConflictsWith = []string{"azure_attributes", "gcp_attributes"}
+	s["azure_attributes"].ConflictsWith = []string{"aws_attributes", "gcp_attributes"}
+	s["gcp_attributes"].ConflictsWith = []string{"aws_attributes", "azure_attributes"}
+
+	s["permanently_delete"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+
+	s["idempotency_token"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		ForceNew: true,
+	}
+
+	s["library"] = &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem:     resourceDatabricksClusterLibraryResource(),
+	}
+
+	s["desired_state"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateStringInSlice([]string{string(models.RUNNING), string(models.TERMINATED)}),
+	}
+
+	s["state"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	s["state_message"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	s["driver_private_ip"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	s["jdbc_port"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	}
+
+	// Spark context IDs do not fit in the integers of 32-bit platforms.
+	s["spark_context_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	s["creator_user_name"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	s["cluster_memory_mb"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	}
+
+	s["cluster_cores"] = &schema.Schema{
+		Type:     schema.TypeFloat,
+		Computed: true,
+	}
+
+	s["default_tags"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+
+	s["start_time"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	}
+
+	s["url"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return s
+}
+
+// resourceDatabricksClusterSpecSchema returns the schema of the attributes
+// describing a cluster. It is shared with the resources that define clusters
+// of their own (e.g., the new_cluster block in databricks_job).
+func resourceDatabricksClusterSpecSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"spark_version": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		// The node type of clusters using an instance pool is given by the
+		// pool, so it is only required when there is no pool. It is not
+		// computed, so that a missing node type is caught when planning.
+		"node_type_id": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			DiffSuppressFunc: resourceDatabricksClusterSuppressPoolNodeType,
+		},
+		"driver_node_type_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"num_workers": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"autoscale": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"min_workers": {
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+					"max_workers": {
+						Type:     schema.TypeInt,
+						Required: true,
+					},
+				},
+			},
+		},
+		"autotermination_minutes": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"spark_conf": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"spark_env_vars": {
+			Type:     schema.TypeMap,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"custom_tags": {
+			Type:             schema.TypeMap,
+			Optional:         true,
+			Elem:             &schema.Schema{Type: schema.TypeString},
+			DiffSuppressFunc: resourceDatabricksClusterSuppressDefaultTags,
+		},
+		"ssh_public_keys": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 10,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"enable_elastic_disk": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"aws_attributes": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"availability": {
+						Type:     schema.TypeString,
+						Optional: true,
+						Computed: true,
+						ValidateFunc: validateStringInSlice([]string{
+							string(models.SPOT),
+							string(models.ON_DEMAND),
+							string(models.SPOT_WITH_FALLBACK),
+						}),
+					},
+					"first_on_demand": {
+						Type:     schema.TypeInt,
+						Optional: true,
+						Computed: true,
+					},
+					"spot_bid_price_percent": {
+						Type:     schema.TypeInt,
+						Optional: true,
+						Computed: true,
+					},
+					"zone_id": {
+						Type:             schema.TypeString,
+						Optional:         true,
+						Computed:         true,
+						DiffSuppressFunc: resourceDatabricksClusterSuppressAutoZone,
+					},
+					"instance_profile_arn": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"ebs_volume_type": {
+						Type:     schema.TypeString,
+						Optional: true,
+						ValidateFunc: validateStringInSlice([]string{
+							string(models.GENERAL_PURPOSE_SSD),
+							string(models.THROUGHPUT_OPTIMIZED_HDD),
+						}),
+					},
+					"ebs_volume_count": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+					"ebs_volume_size": {
+						Type:     schema.TypeInt,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"azure_attributes": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"availability": {
+						Type:     schema.TypeString,
+						Optional: true,
+						Computed: true,
+						ValidateFunc: validateStringInSlice([]string{
+							"SPOT_AZURE",
+							"ON_DEMAND_AZURE",
+							"SPOT_WITH_FALLBACK_AZURE",
+						}),
+					},
+					"first_on_demand": {
+						Type:     schema.TypeInt,
+						Optional: true,
+						Computed: true,
+					},
+					"spot_bid_max_price": {
+						Type:     schema.TypeFloat,
+						Optional: true,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"gcp_attributes": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"google_service_account": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"boot_disk_size": {
+						Type:     schema.TypeInt,
+						Optional: true,
+						Computed: true,
+					},
+					"availability": {
+						Type:     schema.TypeString,
+						Optional: true,
+						Computed: true,
+						ValidateFunc: validateStringInSlice([]string{
+							"PREEMPTIBLE_GCP",
+							"ON_DEMAND_GCP",
+							"PREEMPTIBLE_WITH_FALLBACK_GCP",
+						}),
+					},
+					"local_ssd_count": {
+						Type:     schema.TypeInt,
+						Optional: true,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"cluster_log_conf": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"dbfs": resourceDatabricksClusterDbfsStorageSchema(),
+					"s3":   resourceDatabricksClusterS3StorageSchema(),
+				},
+			},
+		},
+		"init_scripts": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"dbfs":      resourceDatabricksClusterDbfsStorageSchema(),
+					"s3":        resourceDatabricksClusterS3StorageSchema(),
+					"workspace": resourceDatabricksClusterWorkspaceStorageSchema(),
+				},
+			},
+		},
+		"docker_image": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"url": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"basic_auth": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"username": {
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"password": {
+									Type:      schema.TypeString,
+									Required:  true,
+									Sensitive: true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		// The access mode is not computed, so that changes made outside
+		// Terraform show up even when it is not configured.
+		"data_security_mode": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			DiffSuppressFunc: resourceDatabricksClusterSuppressNoAccessMode,
+			ValidateFunc: validateStringInSlice([]string{
+				"NONE",
+				"SINGLE_USER",
+				"USER_ISOLATION",
+				"LEGACY_TABLE_ACL",
+				"LEGACY_PASSTHROUGH",
+				"LEGACY_SINGLE_USER",
+			}),
+		},
+		"single_user_name": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"runtime_engine": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validateStringInSlice([]string{"STANDARD", "PHOTON"}),
+		},
+		"policy_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"instance_pool_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"driver_instance_pool_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"apply_policy_default_values": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+	}
+}
+
+// resourceDatabricksClusterLibraryResource returns the schema of a library,
+// which must set exactly one of its attributes.
+func resourceDatabricksClusterLibraryResource() *schema.Resource {
+	return &schema.Resource{
 		Schema: map[string]*schema.Schema{
-			"name": {
+			"jar": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
-			"spark_version": {
+			"egg": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 			},
-			"node_type_id": {
+			"whl": {
 				Type:     schema.TypeString,
-				Required: true,
-			},
-			"num_workers": {
-				Type:          schema.TypeInt,
-				Optional:      true,
-				ConflictsWith: []string{"autoscale"},
+				Optional: true,
 			},
-			"autoscale": {
-				Type:     schema.TypeSet,
+			"pypi": {
+				Type:     schema.TypeList,
 				Optional: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"min_workers": {
-							Type:     schema.TypeInt,
+						"package": {
+							Type:     schema.TypeString,
 							Required: true,
 						},
-						"max_workers": {
-							Type:     schema.TypeInt,
-							Required: true,
+						"repo": {
+							Type:     schema.TypeString,
+							Optional: true,
 						},
 					},
 				},
-				ConflictsWith: []string{"num_workers"},
-			},
-			"autotermination_minutes": {
-				Type:     schema.TypeInt,
-				Optional: true,
 			},
-			"aws_attributes": {
-				Type:     schema.TypeSet,
+			"maven": {
+				Type:     schema.TypeList,
 				Optional: true,
 				MaxItems: 1,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
-						"zone_id": {
+						"coordinates": {
 							Type:     schema.TypeString,
-							Optional: true,
+							Required: true,
 						},
-						"instance_profile_arn": {
+						"repo": {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
-						"ebs_volume_type": {
-							Type:     schema.TypeString,
+						"exclusions": {
+							Type:     schema.TypeList,
 							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
 						},
-						"ebs_volume_count": {
-							Type:     schema.TypeInt,
-							Optional: true,
+					},
+				},
+			},
+			"cran": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"package": {
+							Type:     schema.TypeString,
+							Required: true,
 						},
-						"ebs_volume_size": {
-							Type:     schema.TypeInt,
+						"repo": {
+							Type:     schema.TypeString,
 							Optional: true,
 						},
 					},
 				},
 			},
-			"permanently_delete": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
+		},
+	}
+}
+
+func resourceDatabricksClusterDbfsStorageSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"destination": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
 			},
 		},
 	}
 }
 
-func resourceDatabricksClusterCreate(d *schema.ResourceData, m interface{}) error {
-	apiClient := m.(*Client).clusters
+func resourceDatabricksClusterS3StorageSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"destination": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"region": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"endpoint": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"enable_encryption": {
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+				"encryption_type": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateStringInSlice([]string{"sse-s3", "sse-kms"}),
+				},
+				"kms_key": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"canned_acl": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
 
-	log.Print("[DEBUG] Creating cluster")
+func resourceDatabricksClusterWorkspaceStorageSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"destination": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+	}
+}
+
+func resourceDatabricksClusterCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	return resourceDatabricksClusterValidateSpec(d, "")
+}
 
-	request := models.ClustersCreateRequest{
-		SparkVersion: d.Get("spark_version").(string),
-		NodeTypeId:   d.Get("node_type_id").(string),
+// resourceDatabricksClusterValidateSpec checks the cluster spec whose
+// attributes start with prefix, as it is shared with the cluster blocks nested
+// within other resources. Values that are not known yet are not checked.
+func resourceDatabricksClusterValidateSpec(d *schema.ResourceDiff, prefix string) error {
+	known := func(keys ...string) bool {
+		for _, key := range keys {
+			if !d.NewValueKnown(prefix + key) {
+				return false
+			}
+		}
+		return true
 	}
 
-	if v, ok := d.GetOk("name"); ok {
-		request.ClusterName = v.(string)
+	var err error
+	switch {
+	case known("node_type_id", "instance_pool_id") &&
+		d.Get(prefix+"node_type_id").(string) == "" && d.Get(prefix+"instance_pool_id").(string) == "":
+		err = errors.New("node_type_id must be set for clusters without instance_pool_id")
+	case known("data_security_mode", "single_user_name"):
+		err = resourceDatabricksClusterValidateSingleUser(
+			d.Get(prefix+"data_security_mode").(string),
+			d.Get(prefix+"single_user_name").(string),
+		)
 	}
 
-	if v, ok := d.GetOk("num_workers"); ok {
-		request.NumWorkers = int32(v.(int))
+	if err != nil && prefix != "" {
+		return fmt.Errorf("%s: %s", strings.TrimSuffix(prefix, "."), err)
 	}
 
-	if v, ok := d.GetOk("autoscale"); ok {
-		autoscale := resourceDatabricksClusterExpandAutoscale(v.(*schema.Set).List())
-		request.Autoscale = &autoscale
+	return err
+}
+
+func resourceDatabricksClusterValidateSingleUser(dataSecurityMode string, singleUserName string) error {
+	singleUser := dataSecurityMode == "SINGLE_USER" || dataSecurityMode == "LEGACY_SINGLE_USER"
+
+	if singleUser && singleUserName == "" {
+		return fmt.Errorf("single_user_name must be set when data_security_mode is %s", dataSecurityMode)
 	}
 
-	if v, ok := d.GetOk("autotermination_minutes"); ok {
-		request.AutoterminationMinutes = int32(v.(int))
+	if !singleUser && singleUserName != "" {
+		return errors.New("single_user_name can only be set when data_security_mode is SINGLE_USER or LEGACY_SINGLE_USER")
 	}
 
-	if v, ok := d.GetOk("aws_attributes"); ok {
-		awsAttributes := resourceDatabricksClusterExpandAwsAttributes(v.(*schema.Set).List())
-		request.AwsAttributes = &awsAttributes
+	return nil
+}
+
+func resourceDatabricksClusterCreate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).clusters
+
+	log.Print("[DEBUG] Creating cluster")
+
+	request := clustersCreateRequest{
+		clusterSpec: resourceDatabricksClusterExpandSpec(resourceDatabricksClusterSpecData(d)),
+	}
+
+	// The token is kept for the lifetime of the resource, and the client
+	// sends it again when it retries a create that failed (e.g., when the
+	// response timed out), so that it never leads to a second cluster.
+	token := d.Get("idempotency_token").(string)
+	if token == "" {
+		token = resource.UniqueId()
+		d.Set("idempotency_token", token)
+	}
+	request.IdempotencyToken = token
+
+	libraries, err := resourceDatabricksClusterExpandLibraries(d.Get("library").(*schema.Set).List())
+	if err != nil {
+		return err
 	}
 
-	resp, err := apiClient.CreateSync(&request)
+	// The steps below share the create timeout, so that the whole creation
+	// takes no longer than configured.
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
+
+	resp, err := apiClient.Create(&request)
 	if err != nil {
 		return err
 	}
 
+	// The ID is stored before waiting, so that clusters that fail to start
+	// are kept in the state (as tainted) and cleaned up by the next apply.
 	d.SetId(resp.ClusterId)
 
 	log.Printf("[DEBUG] Cluster ID: %s", d.Id())
 
-	return nil
+	_, err = resourceDatabricksClusterWaitForState(apiClient, d.Id(), []models.ClustersClusterState{
+		models.RUNNING,
+	}, []models.ClustersClusterState{
+		models.PENDING,
+	}, time.Until(deadline))
+	if err != nil {
+		return err
+	}
+
+	if len(libraries) > 0 {
+		err = resourceDatabricksClusterInstallLibraries(m, d.Id(), libraries, time.Until(deadline))
+		if err != nil {
+			return err
+		}
+	}
+
+	if v, ok := d.GetOk("desired_state"); ok {
+		err = resourceDatabricksClusterReconcileState(apiClient, d.Id(), v.(string), deadline)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceDatabricksClusterRead(d, m)
 }
 
 func resourceDatabricksClusterRead(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*Client).clusters
 
-	request := models.ClustersGetRequest{
+	resp, err := apiClient.Get(&clustersIdRequest{
 		ClusterId: d.Id(),
-	}
-
-	resp, err := apiClient.Get(&request)
+	})
 	if err != nil {
 		if resourceDatabricksClusterNotExistsError(err) {
 			log.Printf("[WARN] Cluster (%s) not found, removing from state", d.Id())
@@ -154,91 +760,711 @@ func resourceDatabricksClusterRead(d *schema.ResourceData, m interface{}) error
 		return err
 	}
 
-	d.Set("name", resp.ClusterName)
-	d.Set("spark_version", resp.SparkVersion)
-	d.Set("node_type_id", resp.NodeTypeId)
-	d.Set("num_workers", resp.NumWorkers)
-	d.Set("autoscale", resourceDatabricksClusterFlattenAutoscale(resp.Autoscale))
-	d.Set("autotermination_minutes", resp.AutoterminationMinutes)
-	d.Set("aws_attributes", resourceDatabricksClusterFlattenAwsAttributes(resp.AwsAttributes))
-
-	return nil
-}
+	customTags := resourceDatabricksClusterRemoveDefaultTags(
+		resp.CustomTags,
+		resp.DefaultTags,
+		d.Get("custom_tags").(map[string]interface{}),
+	)
 
-func resourceDatabricksClusterUpdate(d *schema.ResourceData, m interface{}) error {
-	apiClient := m.(*Client).clusters
+	resourceDatabricksClusterKeepDockerPassword(&resp.clusterSpec, d, "")
 
-	log.Printf("[DEBUG] Updating cluster: %s", d.Id())
+	// Whether policy default values were applied is only known when the
+	// cluster is created or edited, so the configured value is kept.
+	if !resp.ApplyPolicyDefaultValues {
+		resp.ApplyPolicyDefaultValues = d.Get("apply_policy_default_values").(bool)
+	}
 
-	request := models.ClustersEditRequest{}
+	for k, v := range resourceDatabricksClusterFlattenSpec(&resp.clusterSpec) {
+		d.Set(k, v)
+	}
 
-	request.ClusterId = d.Id()
-	request.SparkVersion = d.Get("spark_version").(string)
-	request.NodeTypeId = d.Get("node_type_id").(string)
+	d.Set("custom_tags", customTags)
+	d.Set("state", resp.State)
+	d.Set("state_message", resp.StateMessage)
+	d.Set("jdbc_port", resp.JdbcPort)
+	d.Set("creator_user_name", resp.CreatorUserName)
+	d.Set("cluster_memory_mb", resp.ClusterMemoryMb)
+	d.Set("cluster_cores", resp.ClusterCores)
+	d.Set("default_tags", resp.DefaultTags)
+	d.Set("start_time", resp.StartTime)
+	d.Set("url", resourceDatabricksClusterUrl(m.(*Client).domain, d.Id()))
 
-	if v, ok := d.GetOk("num_workers"); ok {
-		request.NumWorkers = int32(v.(int))
+	// The driver and the Spark context only exist while the cluster runs.
+	if resp.Driver != nil {
+		d.Set("driver_private_ip", resp.Driver.PrivateIp)
+	} else {
+		d.Set("driver_private_ip", "")
 	}
 
-	if v, ok := d.GetOk("autoscale"); ok {
-		autoscale := resourceDatabricksClusterExpandAutoscale(v.(*schema.Set).List())
-		request.Autoscale = &autoscale
+	if resp.SparkContextId != 0 {
+		d.Set("spark_context_id", strconv.FormatInt(resp.SparkContextId, 10))
+	} else {
+		d.Set("spark_context_id", "")
 	}
 
-	if d.HasChange("name") {
-		request.ClusterName = d.Get("name").(string)
+	// The desired state is only tracked when configured, so that a plan
+	// starts or terminates the cluster when it drifted from it.
+	if _, ok := d.GetOk("desired_state"); ok {
+		d.Set("desired_state", resourceDatabricksClusterStableState(resp.State))
 	}
 
-	if d.HasChange("autotermination_minutes") {
-		request.AutoterminationMinutes = int32(d.Get("autotermination_minutes").(int))
-	}
+	// The libraries of a cluster can only be checked while it runs. Only the
+	// libraries in the state are checked, as other libraries may be installed
+	// on the cluster by other means (e.g., the databricks_library resource).
+	if resp.State == models.RUNNING {
+		managed, err := resourceDatabricksClusterExpandLibraries(d.Get("library").(*schema.Set).List())
+		if err != nil {
+			return err
+		}
 
-	if d.HasChange("aws_attributes") {
-		value := d.Get("awsAttributes").(*schema.Set).List()
-		awsAttributes := resourceDatabricksClusterExpandAwsAttributes(value)
-		request.AwsAttributes = &awsAttributes
+		installed, err := resourceDatabricksClusterInstalledLibraries(m, d.Id())
+		if err != nil {
+			return err
+		}
+
+		d.Set("library", resourceDatabricksClusterFlattenLibraries(
+			resourceDatabricksClusterIntersectLibraries(managed, installed),
+		))
 	}
 
-	return apiClient.EditSync(&request)
+	return nil
 }
 
-func resourceDatabricksClusterDelete(d *schema.ResourceData, m interface{}) error {
+func resourceDatabricksClusterUpdate(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*Client).clusters
 
-	log.Printf("[DEBUG] Deleting cluster: %s", d.Id())
+	log.Printf("[DEBUG] Updating cluster: %s", d.Id())
 
-	request := models.ClustersDeleteRequest{
-		ClusterId: d.Id(),
+	// The steps below share the update timeout.
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutUpdate))
+
+	err := resourceDatabricksClusterEdit(d, m, deadline)
+	if err != nil {
+		return err
 	}
 
-	err := apiClient.DeleteSync(&request)
+	err = resourceDatabricksClusterUpdateLibraries(d, m, deadline)
 	if err != nil {
 		return err
 	}
 
-	if d.Get("permanently_delete").(bool) {
-		err := apiClient.PermanentDelete(&models.ClustersPermanentDeleteRequest{
-			ClusterId: d.Id(),
-		})
+	if v, ok := d.GetOk("desired_state"); ok {
+		err = resourceDatabricksClusterReconcileState(apiClient, d.Id(), v.(string), deadline)
 		if err != nil {
 			return err
 		}
 	}
 
-	d.SetId("")
-
-	return nil
+	return resourceDatabricksClusterRead(d, m)
 }
 
-func resourceDatabricksClusterNotExistsError(err error) bool {
-	databricksError, ok := err.(client.Error)
-	return ok &&
-		databricksError.Code() == "INVALID_PARAMETER_VALUE" &&
-		strings.Contains(databricksError.Error(), "does not exist")
-}
+// resourceDatabricksClusterEdit applies the changes in the attributes of the
+// cluster, waiting for them until the given deadline.
+func resourceDatabricksClusterEdit(d *schema.ResourceData, m interface{}, deadline time.Time) error {
+	apiClient := m.(*Client).clusters
 
-func resourceDatabricksClusterExpandAutoscale(autoscale []interface{}) models.ClustersAutoScale {
-	autoscaleElem := autoscale[0].(map[string]interface{})
+	// Editing a running cluster restarts it, so the cluster is only edited
+	// when its attributes change (and not, e.g., when permanently_delete does).
+	if !resourceDatabricksClusterSpecHasChange(d) {
+		return nil
+	}
+
+	// Clusters can only be edited when they are running or terminated.
+	info, err := resourceDatabricksClusterWaitForState(apiClient, d.Id(), []models.ClustersClusterState{
+		models.RUNNING,
+		models.TERMINATED,
+	}, []models.ClustersClusterState{
+		models.PENDING,
+		models.RESTARTING,
+		models.RESIZING,
+		models.TERMINATING,
+	}, time.Until(deadline))
+	if err != nil {
+		return err
+	}
+
+	spec := resourceDatabricksClusterExpandSpec(resourceDatabricksClusterSpecData(d))
+
+	// Running clusters can be resized without restarting them.
+	if info.State == models.RUNNING && resourceDatabricksClusterOnlySizeHasChange(d) {
+		log.Printf("[DEBUG] Resizing cluster: %s", d.Id())
+
+		request := clustersResizeRequest{
+			ClusterId: d.Id(),
+			Autoscale: spec.Autoscale,
+		}
+		if spec.Autoscale == nil {
+			request.NumWorkers = &spec.NumWorkers
+		}
+
+		err = apiClient.Resize(&request)
+		if err != nil {
+			return err
+		}
+
+		return resourceDatabricksClusterWaitForChange(apiClient, d.Id(), []models.ClustersClusterState{
+			models.RESIZING,
+		}, time.Until(deadline))
+	}
+
+	request := clustersEditRequest{
+		ClusterId:   d.Id(),
+		clusterSpec: spec,
+	}
+
+	err = apiClient.Edit(&request)
+	if err != nil {
+		return err
+	}
+
+	// Terminated clusters are edited without being started, so there is
+	// nothing to wait for.
+	if info.State == models.RUNNING {
+		err = resourceDatabricksClusterWaitForChange(apiClient, d.Id(), []models.ClustersClusterState{
+			models.PENDING,
+			models.RESTARTING,
+		}, time.Until(deadline))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabricksClusterDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).clusters
+
+	log.Printf("[DEBUG] Deleting cluster: %s", d.Id())
+
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutDelete))
+
+	err := apiClient.Delete(&clustersIdRequest{
+		ClusterId: d.Id(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = resourceDatabricksClusterWaitForState(apiClient, d.Id(), []models.ClustersClusterState{
+		models.TERMINATED,
+	}, []models.ClustersClusterState{
+		models.PENDING,
+		models.RESTARTING,
+		models.RESIZING,
+		models.TERMINATING,
+	}, time.Until(deadline))
+	if err != nil {
+		return err
+	}
+
+	if d.Get("permanently_delete").(bool) {
+		err := apiClient.PermanentDelete(&clustersIdRequest{
+			ClusterId: d.Id(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksClusterImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	// Attributes that only affect the provider's behaviour cannot be read
+	// from the API, so they start with their default values.
+	d.Set("permanently_delete", false)
+
+	// The installed libraries are not imported, as they may be managed with
+	// databricks_library instead. Those to be managed by the cluster have to
+	// be added to its configuration.
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceDatabricksClusterUrl returns the URL of the page of the cluster in
+// the Databricks UI.
+func resourceDatabricksClusterUrl(domain string, clusterId string) string {
+	return fmt.Sprintf("https://%s/#setting/clusters/%s/configuration", domain, clusterId)
+}
+
+// resourceDatabricksClusterReconcileState starts or terminates the cluster so
+// that it gets into the desired state before the given deadline.
+func resourceDatabricksClusterReconcileState(
+	apiClient *clustersEndpoint,
+	clusterId string,
+	desiredState string,
+	deadline time.Time,
+) error {
+	info, err := resourceDatabricksClusterWaitForState(apiClient, clusterId, []models.ClustersClusterState{
+		models.RUNNING,
+		models.TERMINATED,
+	}, []models.ClustersClusterState{
+		models.PENDING,
+		models.RESTARTING,
+		models.RESIZING,
+		models.TERMINATING,
+	}, time.Until(deadline))
+	if err != nil {
+		return err
+	}
+
+	if string(info.State) == desiredState {
+		return nil
+	}
+
+	switch models.ClustersClusterState(desiredState) {
+	case models.RUNNING:
+		log.Printf("[DEBUG] Starting cluster: %s", clusterId)
+
+		err = apiClient.Start(&clustersIdRequest{
+			ClusterId: clusterId,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = resourceDatabricksClusterWaitForState(apiClient, clusterId, []models.ClustersClusterState{
+			models.RUNNING,
+		}, []models.ClustersClusterState{
+			models.PENDING,
+			models.TERMINATED,
+		}, time.Until(deadline))
+
+	case models.TERMINATED:
+		log.Printf("[DEBUG] Terminating cluster: %s", clusterId)
+
+		err = apiClient.Delete(&clustersIdRequest{
+			ClusterId: clusterId,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = resourceDatabricksClusterWaitForState(apiClient, clusterId, []models.ClustersClusterState{
+			models.TERMINATED,
+		}, []models.ClustersClusterState{
+			models.RUNNING,
+			models.TERMINATING,
+		}, time.Until(deadline))
+	}
+
+	return err
+}
+
+// resourceDatabricksClusterStableState returns the state a cluster ends up in
+// after going through the given one.
+func resourceDatabricksClusterStableState(state models.ClustersClusterState) string {
+	switch state {
+	case models.PENDING, models.RESTARTING, models.RESIZING:
+		return string(models.RUNNING)
+	case models.TERMINATING:
+		return string(models.TERMINATED)
+	default:
+		return string(state)
+	}
+}
+
+// resourceDatabricksClusterWaitForState waits until the cluster gets into one
+// of the target states. Any state other than the target and the pending ones is
+// considered an error, which includes the reason the cluster terminated.
+//
+// The time between polls starts at clusterMinPollInterval and grows
+// exponentially (up to the 10 second limit set by StateChangeConf).
+func resourceDatabricksClusterWaitForState(
+	apiClient *clustersEndpoint,
+	clusterId string,
+	target []models.ClustersClusterState,
+	pending []models.ClustersClusterState,
+	timeout time.Duration,
+) (*clusterInfo, error) {
+	return resourceDatabricksClusterWait(resourceDatabricksClusterStateRefreshFunc(apiClient, clusterId), target, pending, timeout)
+}
+
+// resourceDatabricksClusterWaitForChange waits until a running cluster that
+// was just resized or edited is running again. Right after the request, the
+// cluster may still be seen running before it starts changing, so it is only
+// considered done once it has been seen changing, or running for
+// clusterUnchangedChecks polls in a row (as some changes may go unseen).
+func resourceDatabricksClusterWaitForChange(
+	apiClient *clustersEndpoint,
+	clusterId string,
+	pending []models.ClustersClusterState,
+	timeout time.Duration,
+) error {
+	refresh := resourceDatabricksClusterStateRefreshFunc(apiClient, clusterId)
+
+	changed := false
+	running := 0
+
+	_, err := resourceDatabricksClusterWait(func() (interface{}, string, error) {
+		info, state, err := refresh()
+		if err != nil {
+			return info, state, err
+		}
+
+		if state != string(models.RUNNING) {
+			changed = true
+			return info, state, nil
+		}
+
+		running++
+		if !changed && running < clusterUnchangedChecks {
+			return info, string(clusterStateUnchanged), nil
+		}
+
+		return info, state, nil
+	}, []models.ClustersClusterState{
+		models.RUNNING,
+	}, append(pending, clusterStateUnchanged), timeout)
+
+	return err
+}
+
+func resourceDatabricksClusterWait(
+	refresh resource.StateRefreshFunc,
+	target []models.ClustersClusterState,
+	pending []models.ClustersClusterState,
+	timeout time.Duration,
+) (*clusterInfo, error) {
+	targetStates := make([]string, len(target))
+	for i, state := range target {
+		targetStates[i] = string(state)
+	}
+
+	pendingStates := make([]string, len(pending))
+	for i, state := range pending {
+		pendingStates[i] = string(state)
+	}
+
+	conf := &resource.StateChangeConf{
+		Pending:    pendingStates,
+		Target:     targetStates,
+		Refresh:    refresh,
+		Timeout:    timeout,
+		MinTimeout: clusterMinPollInterval,
+	}
+
+	result, err := conf.WaitForState()
+	if err != nil {
+		if _, ok := err.(*resource.UnexpectedStateError); ok {
+			if info, ok := result.(*clusterInfo); ok && info != nil {
+				return nil, resourceDatabricksClusterStateError(info)
+			}
+		}
+		return nil, err
+	}
+
+	return result.(*clusterInfo), nil
+}
+
+// resourceDatabricksClusterStateError builds an error describing why a cluster
+// got into an unexpected state.
+func resourceDatabricksClusterStateError(info *clusterInfo) error {
+	msg := fmt.Sprintf("unexpected state (%s) for cluster %s", info.State, info.ClusterId)
+
+	if info.StateMessage != "" {
+		msg += fmt.Sprintf(": %s", info.StateMessage)
+	}
+
+	if info.TerminationReason != nil && info.TerminationReason.Code != nil {
+		msg += fmt.Sprintf(" (termination code: %s", *info.TerminationReason.Code)
+
+		keys := make([]string, 0, len(info.TerminationReason.Parameters))
+		for k := range info.TerminationReason.Parameters {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		params := make([]string, len(keys))
+		for i, k := range keys {
+			params[i] = fmt.Sprintf("%s=%s", k, info.TerminationReason.Parameters[k])
+		}
+
+		if len(params) > 0 {
+			msg += fmt.Sprintf(", parameters: %s", strings.Join(params, ", "))
+		}
+
+		msg += ")"
+	}
+
+	return errors.New(msg)
+}
+
+func resourceDatabricksClusterStateRefreshFunc(apiClient *clustersEndpoint, clusterId string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := apiClient.Get(&clustersIdRequest{
+			ClusterId: clusterId,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		return resp, string(resp.State), nil
+	}
+}
+
+func resourceDatabricksClusterNotExistsError(err error) bool {
+	databricksError, ok := err.(client.Error)
+	return ok &&
+		databricksError.Code() == "INVALID_PARAMETER_VALUE" &&
+		strings.Contains(databricksError.Error(), "does not exist")
+}
+
+// resourceDatabricksClusterSpecHasChange checks whether any of the attributes
+// describing the cluster changed.
+func resourceDatabricksClusterSpecHasChange(d *schema.ResourceData) bool {
+	for k := range resourceDatabricksClusterSpecSchema() {
+		if d.HasChange(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceDatabricksClusterOnlySizeHasChange checks whether the number of
+// workers (or the autoscale range) is the only attribute of the cluster that
+// changed.
+func resourceDatabricksClusterOnlySizeHasChange(d *schema.ResourceData) bool {
+	for k := range resourceDatabricksClusterSpecSchema() {
+		if k != "num_workers" && k != "autoscale" && d.HasChange(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceDatabricksClusterSpecData gathers the cluster attributes of a
+// resource into a map, so that they can be expanded the same way nested
+// cluster blocks are.
+func resourceDatabricksClusterSpecData(d *schema.ResourceData) map[string]interface{} {
+	spec := make(map[string]interface{})
+	for k := range resourceDatabricksClusterSpecSchema() {
+		spec[k] = d.Get(k)
+	}
+	return spec
+}
+
+func resourceDatabricksClusterExpandSpec(spec map[string]interface{}) clusterSpec {
+	request := clusterSpec{
+		SparkVersion: spec["spark_version"].(string),
+		NodeTypeId:   spec["node_type_id"].(string),
+	}
+
+	if v, ok := spec["name"]; ok {
+		request.ClusterName = v.(string)
+	}
+
+	if v, ok := spec["num_workers"]; ok {
+		request.NumWorkers = int32(v.(int))
+	}
+
+	if v, ok := spec["autoscale"]; ok && v.(*schema.Set).Len() > 0 {
+		autoscale := resourceDatabricksClusterExpandAutoscale(v.(*schema.Set).List())
+		request.Autoscale = &autoscale
+	}
+
+	if v, ok := spec["driver_node_type_id"]; ok {
+		request.DriverNodeTypeId = v.(string)
+	}
+
+	if v, ok := spec["autotermination_minutes"]; ok {
+		request.AutoterminationMinutes = int32(v.(int))
+	}
+
+	if v, ok := spec["spark_conf"]; ok {
+		request.SparkConf = expandStringMap(v.(map[string]interface{}))
+	}
+
+	if v, ok := spec["spark_env_vars"]; ok {
+		request.SparkEnvVars = expandStringMap(v.(map[string]interface{}))
+	}
+
+	if v, ok := spec["custom_tags"]; ok {
+		request.CustomTags = expandStringMap(v.(map[string]interface{}))
+	}
+
+	if v, ok := spec["ssh_public_keys"]; ok {
+		request.SshPublicKeys = expandStringList(v.([]interface{}))
+	}
+
+	if v, ok := spec["enable_elastic_disk"]; ok {
+		request.EnableElasticDisk = v.(bool)
+	}
+
+	if v, ok := spec["aws_attributes"]; ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		awsAttributes := resourceDatabricksClusterExpandAwsAttributes(v.([]interface{}))
+		request.AwsAttributes = &awsAttributes
+	}
+
+	if v, ok := spec["azure_attributes"]; ok {
+		request.AzureAttributes = resourceDatabricksClusterExpandAzureAttributes(v.([]interface{}))
+	}
+
+	if v, ok := spec["gcp_attributes"]; ok {
+		request.GcpAttributes = resourceDatabricksClusterExpandGcpAttributes(v.([]interface{}))
+	}
+
+	if v, ok := spec["cluster_log_conf"]; ok {
+		request.ClusterLogConf = resourceDatabricksClusterExpandClusterLogConf(v.([]interface{}))
+	}
+
+	if v, ok := spec["init_scripts"]; ok {
+		request.InitScripts = resourceDatabricksClusterExpandInitScripts(v.([]interface{}))
+	}
+
+	if v, ok := spec["docker_image"]; ok {
+		request.DockerImage = resourceDatabricksClusterExpandDockerImage(v.([]interface{}))
+	}
+
+	if v, ok := spec["instance_pool_id"]; ok {
+		request.InstancePoolId = v.(string)
+	}
+
+	// The driver pool read from the API is stale once the cluster no longer
+	// uses a pool, as it cannot be used without one.
+	if v, ok := spec["driver_instance_pool_id"]; ok && request.InstancePoolId != "" {
+		request.DriverInstancePoolId = v.(string)
+	}
+
+	// The node types of clusters using instance pools are given by the pools,
+	// so the ones read from the API are not sent back.
+	if request.InstancePoolId != "" {
+		request.NodeTypeId = ""
+	}
+
+	if request.DriverInstancePoolId != "" {
+		request.DriverNodeTypeId = ""
+	}
+
+	if v, ok := spec["data_security_mode"]; ok {
+		request.DataSecurityMode = v.(string)
+	}
+
+	if v, ok := spec["single_user_name"]; ok {
+		request.SingleUserName = v.(string)
+	}
+
+	if v, ok := spec["runtime_engine"]; ok {
+		request.RuntimeEngine = v.(string)
+	}
+
+	if v, ok := spec["policy_id"]; ok {
+		request.PolicyId = v.(string)
+	}
+
+	if v, ok := spec["apply_policy_default_values"]; ok {
+		request.ApplyPolicyDefaultValues = v.(bool)
+	}
+
+	return request
+}
+
+func resourceDatabricksClusterFlattenSpec(spec *clusterSpec) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                        spec.ClusterName,
+		"spark_version":               spec.SparkVersion,
+		"node_type_id":                spec.NodeTypeId,
+		"driver_node_type_id":         spec.DriverNodeTypeId,
+		"num_workers":                 int(spec.NumWorkers),
+		"autoscale":                   resourceDatabricksClusterFlattenAutoscale(spec.Autoscale),
+		"autotermination_minutes":     int(spec.AutoterminationMinutes),
+		"spark_conf":                  spec.SparkConf,
+		"spark_env_vars":              spec.SparkEnvVars,
+		"custom_tags":                 spec.CustomTags,
+		"ssh_public_keys":             spec.SshPublicKeys,
+		"enable_elastic_disk":         spec.EnableElasticDisk,
+		"aws_attributes":              resourceDatabricksClusterFlattenAwsAttributes(spec.AwsAttributes),
+		"azure_attributes":            resourceDatabricksClusterFlattenAzureAttributes(spec.AzureAttributes),
+		"gcp_attributes":              resourceDatabricksClusterFlattenGcpAttributes(spec.GcpAttributes),
+		"cluster_log_conf":            resourceDatabricksClusterFlattenClusterLogConf(spec.ClusterLogConf),
+		"init_scripts":                resourceDatabricksClusterFlattenInitScripts(spec.InitScripts),
+		"docker_image":                resourceDatabricksClusterFlattenDockerImage(spec.DockerImage),
+		"instance_pool_id":            spec.InstancePoolId,
+		"driver_instance_pool_id":     spec.DriverInstancePoolId,
+		"data_security_mode":          spec.DataSecurityMode,
+		"single_user_name":            spec.SingleUserName,
+		"runtime_engine":              spec.RuntimeEngine,
+		"policy_id":                   spec.PolicyId,
+		"apply_policy_default_values": spec.ApplyPolicyDefaultValues,
+	}
+}
+
+// resourceDatabricksClusterKeepDockerPassword sets the docker password of the
+// spec read from the API to the one in the state, as the API does not return
+// it. The prefix locates the spec in the state, as it is shared with the
+// cluster blocks nested within other resources.
+func resourceDatabricksClusterKeepDockerPassword(spec *clusterSpec, d *schema.ResourceData, prefix string) {
+	if spec == nil || spec.DockerImage == nil || spec.DockerImage.BasicAuth == nil || spec.DockerImage.BasicAuth.Password != "" {
+		return
+	}
+
+	spec.DockerImage.BasicAuth.Password = d.Get(prefix + "docker_image.0.basic_auth.0.password").(string)
+}
+
+// resourceDatabricksClusterDefaultTags are the tags that Databricks adds to
+// every cluster on its own.
+var resourceDatabricksClusterDefaultTags = []string{
+	"Vendor",
+	"Creator",
+	"ClusterName",
+	"ClusterId",
+}
+
+// resourceDatabricksClusterRemoveDefaultTags removes from the tags of a
+// cluster the ones that Databricks added by default, unless they are also
+// part of the configured tags.
+func resourceDatabricksClusterRemoveDefaultTags(
+	tags map[string]string,
+	defaultTags map[string]string,
+	configured map[string]interface{},
+) map[string]string {
+	result := make(map[string]string, len(tags))
+	for k, v := range tags {
+		if defaultValue, ok := defaultTags[k]; ok && defaultValue == v {
+			if _, ok := configured[k]; !ok {
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// resourceDatabricksClusterSuppressDefaultTags suppresses the diffs caused
+// by default tags that are part of the state but not of the configuration.
+func resourceDatabricksClusterSuppressDefaultTags(k, old, new string, d *schema.ResourceData) bool {
+	// The key of the map attribute is needed, as the schema is shared with
+	// the cluster blocks nested within other resources.
+	key := k[:strings.Index(k, "custom_tags")+len("custom_tags")]
+
+	o, n := d.GetChange(key)
+	oldTags := o.(map[string]interface{})
+	newTags := n.(map[string]interface{})
+
+	for _, tag := range resourceDatabricksClusterDefaultTags {
+		if _, ok := newTags[tag]; !ok {
+			delete(oldTags, tag)
+		}
+	}
+
+	if len(oldTags) != len(newTags) {
+		return false
+	}
+
+	for tag, v := range newTags {
+		if oldTags[tag] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func resourceDatabricksClusterExpandAutoscale(autoscale []interface{}) models.ClustersAutoScale {
+	autoscaleElem := autoscale[0].(map[string]interface{})
 
 	return models.ClustersAutoScale{
 		MinWorkers: int32(autoscaleElem["min_workers"].(int)),
@@ -246,22 +1472,59 @@ func resourceDatabricksClusterExpandAutoscale(autoscale []interface{}) models.Cl
 	}
 }
 
-func resourceDatabricksClusterFlattenAutoscale(autoscale *models.ClustersAutoScale) []map[string]interface{} {
-	result := make([]map[string]interface{}, 0)
+// resourceDatabricksClusterFlattenAutoscale returns a set rather than a slice,
+// as Terraform fails to convert slices into sets nested within lists (e.g.,
+// in the new_cluster blocks of jobs).
+func resourceDatabricksClusterFlattenAutoscale(autoscale *models.ClustersAutoScale) *schema.Set {
+	elem := resourceDatabricksClusterSpecSchema()["autoscale"].Elem.(*schema.Resource)
+
+	result := schema.NewSet(schema.HashResource(elem), nil)
 	if autoscale != nil {
-		result = append(result, map[string]interface{}{
-			"min_workers": autoscale.MinWorkers,
-			"max_workers": autoscale.MaxWorkers,
+		result.Add(map[string]interface{}{
+			"min_workers": int(autoscale.MinWorkers),
+			"max_workers": int(autoscale.MaxWorkers),
 		})
 	}
 	return result
 }
 
+// resourceDatabricksClusterSuppressPoolNodeType ignores the node type read for
+// clusters using an instance pool when it is not configured.
+func resourceDatabricksClusterSuppressPoolNodeType(k, old, new string, d *schema.ResourceData) bool {
+	prefix := strings.TrimSuffix(k, "node_type_id")
+	return new == "" && d.Get(prefix+"instance_pool_id").(string) != ""
+}
+
+// resourceDatabricksClusterSuppressNoAccessMode ignores the NONE access mode
+// read for clusters that do not configure one, as both are the same.
+func resourceDatabricksClusterSuppressNoAccessMode(k, old, new string, d *schema.ResourceData) bool {
+	return old == "NONE" && new == ""
+}
+
+// resourceDatabricksClusterSuppressAutoZone suppresses the diffs between the
+// zone chosen by Databricks and the "auto" zone it was chosen for.
+func resourceDatabricksClusterSuppressAutoZone(k, old, new string, d *schema.ResourceData) bool {
+	return old != "" && strings.EqualFold(new, "auto")
+}
+
 func resourceDatabricksClusterExpandAwsAttributes(awsAttributes []interface{}) models.ClustersAwsAttributes {
 	awsAttributesElem := awsAttributes[0].(map[string]interface{})
 
 	result := models.ClustersAwsAttributes{}
 
+	if v, ok := awsAttributesElem["availability"]; ok && v.(string) != "" {
+		availability := models.ClustersAwsAvailability(v.(string))
+		result.Availability = &availability
+	}
+
+	if v, ok := awsAttributesElem["first_on_demand"]; ok {
+		result.FirstOnDemand = int32(v.(int))
+	}
+
+	if v, ok := awsAttributesElem["spot_bid_price_percent"]; ok {
+		result.SpotBidPricePercent = int32(v.(int))
+	}
+
 	if v, ok := awsAttributesElem["zone_id"]; ok {
 		result.ZoneId = v.(string)
 	}
@@ -270,7 +1533,7 @@ func resourceDatabricksClusterExpandAwsAttributes(awsAttributes []interface{}) m
 		result.InstanceProfileArn = v.(string)
 	}
 
-	if v, ok := awsAttributesElem["ebs_volume_type"]; ok {
+	if v, ok := awsAttributesElem["ebs_volume_type"]; ok && v.(string) != "" {
 		volumeType := models.ClustersEbsVolumeType(v.(string))
 		result.EbsVolumeType = &volumeType
 	}
@@ -290,6 +1553,11 @@ func resourceDatabricksClusterFlattenAwsAttributes(awsAttributes *models.Cluster
 	result := make([]map[string]interface{}, 0)
 	if awsAttributes != nil {
 		attrs := make(map[string]interface{})
+		if awsAttributes.Availability != nil {
+			attrs["availability"] = string(*awsAttributes.Availability)
+		}
+		attrs["first_on_demand"] = int(awsAttributes.FirstOnDemand)
+		attrs["spot_bid_price_percent"] = int(awsAttributes.SpotBidPricePercent)
 		attrs["zone_id"] = awsAttributes.ZoneId
 		attrs["instance_profile_arn"] = awsAttributes.InstanceProfileArn
 		if awsAttributes.EbsVolumeType != nil {
@@ -303,3 +1571,554 @@ func resourceDatabricksClusterFlattenAwsAttributes(awsAttributes *models.Cluster
 
 	return result
 }
+
+func resourceDatabricksClusterExpandAzureAttributes(azureAttributes []interface{}) *clusterAzureAttributes {
+	if len(azureAttributes) == 0 || azureAttributes[0] == nil {
+		return nil
+	}
+
+	azureAttributesElem := azureAttributes[0].(map[string]interface{})
+
+	return &clusterAzureAttributes{
+		FirstOnDemand:   int32(azureAttributesElem["first_on_demand"].(int)),
+		Availability:    azureAttributesElem["availability"].(string),
+		SpotBidMaxPrice: azureAttributesElem["spot_bid_max_price"].(float64),
+	}
+}
+
+func resourceDatabricksClusterFlattenAzureAttributes(azureAttributes *clusterAzureAttributes) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if azureAttributes != nil {
+		result = append(result, map[string]interface{}{
+			"first_on_demand":    int(azureAttributes.FirstOnDemand),
+			"availability":       azureAttributes.Availability,
+			"spot_bid_max_price": azureAttributes.SpotBidMaxPrice,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterExpandGcpAttributes(gcpAttributes []interface{}) *clusterGcpAttributes {
+	if len(gcpAttributes) == 0 || gcpAttributes[0] == nil {
+		return nil
+	}
+
+	gcpAttributesElem := gcpAttributes[0].(map[string]interface{})
+
+	return &clusterGcpAttributes{
+		GoogleServiceAccount: gcpAttributesElem["google_service_account"].(string),
+		BootDiskSize:         int32(gcpAttributesElem["boot_disk_size"].(int)),
+		Availability:         gcpAttributesElem["availability"].(string),
+		LocalSsdCount:        int32(gcpAttributesElem["local_ssd_count"].(int)),
+	}
+}
+
+func resourceDatabricksClusterFlattenGcpAttributes(gcpAttributes *clusterGcpAttributes) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if gcpAttributes != nil {
+		result = append(result, map[string]interface{}{
+			"google_service_account": gcpAttributes.GoogleServiceAccount,
+			"boot_disk_size":         int(gcpAttributes.BootDiskSize),
+			"availability":           gcpAttributes.Availability,
+			"local_ssd_count":        int(gcpAttributes.LocalSsdCount),
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterExpandClusterLogConf(logConf []interface{}) *clusterLogConf {
+	if len(logConf) == 0 || logConf[0] == nil {
+		return nil
+	}
+
+	logConfElem := logConf[0].(map[string]interface{})
+
+	return &clusterLogConf{
+		Dbfs: resourceDatabricksClusterExpandDbfsStorage(logConfElem["dbfs"].([]interface{})),
+		S3:   resourceDatabricksClusterExpandS3Storage(logConfElem["s3"].([]interface{})),
+	}
+}
+
+func resourceDatabricksClusterFlattenClusterLogConf(logConf *clusterLogConf) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if logConf != nil {
+		result = append(result, map[string]interface{}{
+			"dbfs": resourceDatabricksClusterFlattenDbfsStorage(logConf.Dbfs),
+			"s3":   resourceDatabricksClusterFlattenS3Storage(logConf.S3),
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterExpandInitScripts(initScripts []interface{}) []clusterInitScriptInfo {
+	result := make([]clusterInitScriptInfo, 0, len(initScripts))
+	for _, initScript := range initScripts {
+		if initScript == nil {
+			continue
+		}
+
+		initScriptElem := initScript.(map[string]interface{})
+
+		result = append(result, clusterInitScriptInfo{
+			Dbfs:      resourceDatabricksClusterExpandDbfsStorage(initScriptElem["dbfs"].([]interface{})),
+			S3:        resourceDatabricksClusterExpandS3Storage(initScriptElem["s3"].([]interface{})),
+			Workspace: resourceDatabricksClusterExpandWorkspaceStorage(initScriptElem["workspace"].([]interface{})),
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterFlattenInitScripts(initScripts []clusterInitScriptInfo) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(initScripts))
+	for _, initScript := range initScripts {
+		result = append(result, map[string]interface{}{
+			"dbfs":      resourceDatabricksClusterFlattenDbfsStorage(initScript.Dbfs),
+			"s3":        resourceDatabricksClusterFlattenS3Storage(initScript.S3),
+			"workspace": resourceDatabricksClusterFlattenWorkspaceStorage(initScript.Workspace),
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterExpandDbfsStorage(storage []interface{}) *clusterDbfsStorageInfo {
+	if len(storage) == 0 || storage[0] == nil {
+		return nil
+	}
+
+	storageElem := storage[0].(map[string]interface{})
+
+	return &clusterDbfsStorageInfo{
+		Destination: storageElem["destination"].(string),
+	}
+}
+
+func resourceDatabricksClusterFlattenDbfsStorage(storage *clusterDbfsStorageInfo) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if storage != nil {
+		result = append(result, map[string]interface{}{
+			"destination": storage.Destination,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterExpandS3Storage(storage []interface{}) *clusterS3StorageInfo {
+	if len(storage) == 0 || storage[0] == nil {
+		return nil
+	}
+
+	storageElem := storage[0].(map[string]interface{})
+
+	return &clusterS3StorageInfo{
+		Destination:      storageElem["destination"].(string),
+		Region:           storageElem["region"].(string),
+		Endpoint:         storageElem["endpoint"].(string),
+		EnableEncryption: storageElem["enable_encryption"].(bool),
+		EncryptionType:   storageElem["encryption_type"].(string),
+		KmsKey:           storageElem["kms_key"].(string),
+		CannedAcl:        storageElem["canned_acl"].(string),
+	}
+}
+
+func resourceDatabricksClusterFlattenS3Storage(storage *clusterS3StorageInfo) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if storage != nil {
+		result = append(result, map[string]interface{}{
+			"destination":       storage.Destination,
+			"region":            storage.Region,
+			"endpoint":          storage.Endpoint,
+			"enable_encryption": storage.EnableEncryption,
+			"encryption_type":   storage.EncryptionType,
+			"kms_key":           storage.KmsKey,
+			"canned_acl":        storage.CannedAcl,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterExpandWorkspaceStorage(storage []interface{}) *clusterWorkspaceStorageInfo {
+	if len(storage) == 0 || storage[0] == nil {
+		return nil
+	}
+
+	storageElem := storage[0].(map[string]interface{})
+
+	return &clusterWorkspaceStorageInfo{
+		Destination: storageElem["destination"].(string),
+	}
+}
+
+func resourceDatabricksClusterFlattenWorkspaceStorage(storage *clusterWorkspaceStorageInfo) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if storage != nil {
+		result = append(result, map[string]interface{}{
+			"destination": storage.Destination,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksClusterExpandDockerImage(dockerImage []interface{}) *clusterDockerImage {
+	if len(dockerImage) == 0 || dockerImage[0] == nil {
+		return nil
+	}
+
+	dockerImageElem := dockerImage[0].(map[string]interface{})
+
+	result := clusterDockerImage{
+		Url: dockerImageElem["url"].(string),
+	}
+
+	if v := dockerImageElem["basic_auth"].([]interface{}); len(v) > 0 && v[0] != nil {
+		basicAuthElem := v[0].(map[string]interface{})
+		result.BasicAuth = &clusterDockerBasicAuth{
+			Username: basicAuthElem["username"].(string),
+			Password: basicAuthElem["password"].(string),
+		}
+	}
+
+	return &result
+}
+
+func resourceDatabricksClusterFlattenDockerImage(dockerImage *clusterDockerImage) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if dockerImage != nil {
+		basicAuth := make([]map[string]interface{}, 0)
+		if dockerImage.BasicAuth != nil {
+			basicAuth = append(basicAuth, map[string]interface{}{
+				"username": dockerImage.BasicAuth.Username,
+				"password": dockerImage.BasicAuth.Password,
+			})
+		}
+
+		result = append(result, map[string]interface{}{
+			"url":        dockerImage.Url,
+			"basic_auth": basicAuth,
+		})
+	}
+	return result
+}
+
+// resourceDatabricksClusterUpdateLibraries installs the libraries added to the
+// cluster and uninstalls the removed ones, waiting for the installation until
+// the given deadline.
+func resourceDatabricksClusterUpdateLibraries(d *schema.ResourceData, m interface{}, deadline time.Time) error {
+	apiClient := m.(*Client).libraries
+
+	if !d.HasChange("library") {
+		return nil
+	}
+
+	o, n := d.GetChange("library")
+
+	removed, err := resourceDatabricksClusterExpandLibraries(o.(*schema.Set).Difference(n.(*schema.Set)).List())
+	if err != nil {
+		return err
+	}
+
+	added, err := resourceDatabricksClusterExpandLibraries(n.(*schema.Set).Difference(o.(*schema.Set)).List())
+	if err != nil {
+		return err
+	}
+
+	if len(removed) > 0 {
+		err = apiClient.Uninstall(&librariesRequest{
+			ClusterId: d.Id(),
+			Libraries: removed,
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[WARN] Libraries removed from cluster (%s) are uninstalled when it restarts", d.Id())
+	}
+
+	if len(added) > 0 {
+		return resourceDatabricksClusterInstallLibraries(m, d.Id(), added, time.Until(deadline))
+	}
+
+	return nil
+}
+
+// resourceDatabricksClusterInstallLibraries installs the libraries on the
+// cluster. If the cluster is running, it waits until they are installed.
+// Otherwise, they are installed the next time the cluster starts.
+func resourceDatabricksClusterInstallLibraries(
+	m interface{},
+	clusterId string,
+	libraries []library,
+	timeout time.Duration,
+) error {
+	apiClient := m.(*Client).libraries
+
+	log.Printf("[DEBUG] Installing libraries on cluster: %s", clusterId)
+
+	err := apiClient.Install(&librariesRequest{
+		ClusterId: clusterId,
+		Libraries: libraries,
+	})
+	if err != nil {
+		return err
+	}
+
+	info, err := m.(*Client).clusters.Get(&clustersIdRequest{
+		ClusterId: clusterId,
+	})
+	if err != nil {
+		return err
+	}
+
+	if info.State != models.RUNNING {
+		return nil
+	}
+
+	conf := &resource.StateChangeConf{
+		Pending:    []string{"INSTALLING"},
+		Target:     []string{"INSTALLED"},
+		Refresh:    resourceDatabricksClusterLibrariesRefreshFunc(apiClient, clusterId, libraries),
+		Timeout:    timeout,
+		MinTimeout: clusterMinPollInterval,
+	}
+
+	_, err = conf.WaitForState()
+	return err
+}
+
+// resourceDatabricksClusterLibrariesRefreshFunc returns INSTALLED when all the
+// libraries are installed, and fails if any of them could not be installed
+// (including the ones that were skipped or are pending uninstall).
+func resourceDatabricksClusterLibrariesRefreshFunc(
+	apiClient *librariesEndpoint,
+	clusterId string,
+	libraries []library,
+) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		resp, err := apiClient.ClusterStatus(&librariesClusterStatusRequest{
+			ClusterId: clusterId,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+
+		statuses := make(map[string]libraryFullStatus, len(resp.LibraryStatuses))
+		for _, status := range resp.LibraryStatuses {
+			if status.Library != nil {
+				statuses[resourceDatabricksClusterLibraryKey(*status.Library)] = status
+			}
+		}
+
+		state := "INSTALLED"
+		var failures []string
+
+		for _, l := range libraries {
+			status, ok := statuses[resourceDatabricksClusterLibraryKey(l)]
+			switch {
+			case !ok:
+				state = "INSTALLING"
+			case status.Status == "FAILED" || status.Status == "SKIPPED":
+				failures = append(failures, fmt.Sprintf(
+					"%s (%s)",
+					resourceDatabricksClusterDescribeLibrary(l),
+					strings.Join(status.Messages, "; "),
+				))
+			case status.Status == "UNINSTALL_ON_RESTART":
+				// The library was uninstalled before, and cannot be installed
+				// again until the cluster restarts.
+				failures = append(failures, fmt.Sprintf(
+					"%s (pending uninstall, the cluster must be restarted first)",
+					resourceDatabricksClusterDescribeLibrary(l),
+				))
+			case status.Status != "INSTALLED":
+				state = "INSTALLING"
+			}
+		}
+
+		if len(failures) > 0 {
+			return nil, "", fmt.Errorf(
+				"failed to install libraries on cluster %s: %s",
+				clusterId,
+				strings.Join(failures, ", "),
+			)
+		}
+
+		return resp, state, nil
+	}
+}
+
+// resourceDatabricksClusterInstalledLibraries returns the libraries installed
+// on the cluster, skipping the ones installed on all clusters and the ones
+// that will be uninstalled when the cluster restarts.
+func resourceDatabricksClusterInstalledLibraries(m interface{}, clusterId string) ([]library, error) {
+	apiClient := m.(*Client).libraries
+
+	resp, err := apiClient.ClusterStatus(&librariesClusterStatusRequest{
+		ClusterId: clusterId,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]library, 0, len(resp.LibraryStatuses))
+	for _, status := range resp.LibraryStatuses {
+		if status.Library == nil || status.IsLibraryForAllClusters || status.Status == "UNINSTALL_ON_RESTART" {
+			continue
+		}
+		result = append(result, *status.Library)
+	}
+
+	return result, nil
+}
+
+// resourceDatabricksClusterIntersectLibraries returns the libraries in
+// managed that are also in installed.
+func resourceDatabricksClusterIntersectLibraries(managed []library, installed []library) []library {
+	keys := make(map[string]bool, len(installed))
+	for _, l := range installed {
+		keys[resourceDatabricksClusterLibraryKey(l)] = true
+	}
+
+	result := make([]library, 0, len(managed))
+	for _, l := range managed {
+		if keys[resourceDatabricksClusterLibraryKey(l)] {
+			result = append(result, l)
+		}
+	}
+
+	return result
+}
+
+// resourceDatabricksClusterLibraryKey identifies a library, so that the ones
+// returned by the API can be matched with the ones in the state.
+func resourceDatabricksClusterLibraryKey(l library) string {
+	key, _ := json.Marshal(l)
+	return string(key)
+}
+
+func resourceDatabricksClusterDescribeLibrary(l library) string {
+	switch {
+	case l.Jar != "":
+		return "jar " + l.Jar
+	case l.Egg != "":
+		return "egg " + l.Egg
+	case l.Whl != "":
+		return "whl " + l.Whl
+	case l.Pypi != nil:
+		return "pypi " + l.Pypi.Package
+	case l.Maven != nil:
+		return "maven " + l.Maven.Coordinates
+	case l.Cran != nil:
+		return "cran " + l.Cran.Package
+	default:
+		return "unknown library"
+	}
+}
+
+func resourceDatabricksClusterExpandLibraries(libraries []interface{}) ([]library, error) {
+	result := make([]library, 0, len(libraries))
+	for _, l := range libraries {
+		expanded, err := resourceDatabricksClusterExpandLibrary(l.(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *expanded)
+	}
+	return result, nil
+}
+
+func resourceDatabricksClusterExpandLibrary(libraryElem map[string]interface{}) (*library, error) {
+	result := library{
+		Jar: libraryElem["jar"].(string),
+		Egg: libraryElem["egg"].(string),
+		Whl: libraryElem["whl"].(string),
+	}
+
+	count := 0
+	for _, v := range []string{result.Jar, result.Egg, result.Whl} {
+		if v != "" {
+			count++
+		}
+	}
+
+	if v := libraryElem["pypi"].([]interface{}); len(v) > 0 && v[0] != nil {
+		pypiElem := v[0].(map[string]interface{})
+		result.Pypi = &libraryPythonPyPi{
+			Package: pypiElem["package"].(string),
+			Repo:    pypiElem["repo"].(string),
+		}
+		count++
+	}
+
+	if v := libraryElem["maven"].([]interface{}); len(v) > 0 && v[0] != nil {
+		mavenElem := v[0].(map[string]interface{})
+		result.Maven = &libraryMaven{
+			Coordinates: mavenElem["coordinates"].(string),
+			Repo:        mavenElem["repo"].(string),
+			Exclusions:  expandStringList(mavenElem["exclusions"].([]interface{})),
+		}
+		count++
+	}
+
+	if v := libraryElem["cran"].([]interface{}); len(v) > 0 && v[0] != nil {
+		cranElem := v[0].(map[string]interface{})
+		result.Cran = &libraryRCran{
+			Package: cranElem["package"].(string),
+			Repo:    cranElem["repo"].(string),
+		}
+		count++
+	}
+
+	if count != 1 {
+		return nil, errors.New("exactly one of jar, egg, whl, pypi, maven or cran must be set in a library")
+	}
+
+	return &result, nil
+}
+
+func resourceDatabricksClusterFlattenLibraries(libraries []library) []interface{} {
+	result := make([]interface{}, 0, len(libraries))
+	for _, l := range libraries {
+		result = append(result, resourceDatabricksClusterFlattenLibrary(l))
+	}
+	return result
+}
+
+func resourceDatabricksClusterFlattenLibrary(l library) map[string]interface{} {
+	pypi := make([]interface{}, 0)
+	if l.Pypi != nil {
+		pypi = append(pypi, map[string]interface{}{
+			"package": l.Pypi.Package,
+			"repo":    l.Pypi.Repo,
+		})
+	}
+
+	maven := make([]interface{}, 0)
+	if l.Maven != nil {
+		exclusions := make([]interface{}, 0, len(l.Maven.Exclusions))
+		for _, exclusion := range l.Maven.Exclusions {
+			exclusions = append(exclusions, exclusion)
+		}
+
+		maven = append(maven, map[string]interface{}{
+			"coordinates": l.Maven.Coordinates,
+			"repo":        l.Maven.Repo,
+			"exclusions":  exclusions,
+		})
+	}
+
+	cran := make([]interface{}, 0)
+	if l.Cran != nil {
+		cran = append(cran, map[string]interface{}{
+			"package": l.Cran.Package,
+			"repo":    l.Cran.Repo,
+		})
+	}
+
+	return map[string]interface{}{
+		"jar":   l.Jar,
+		"egg":   l.Egg,
+		"whl":   l.Whl,
+		"pypi":  pypi,
+		"maven": maven,
+		"cran":  cran,
+	}
+}
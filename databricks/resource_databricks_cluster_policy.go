@@ -0,0 +1,173 @@
+package databricks
+
+import (
+	"encoding/json"
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"reflect"
+)
+
+func resourceDatabricksClusterPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatabricksClusterPolicyCreate,
+		Read:   resourceDatabricksClusterPolicyRead,
+		Update: resourceDatabricksClusterPolicyUpdate,
+		Delete: resourceDatabricksClusterPolicyDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			// Policies based on a family get their definition from it, so it
+			// is computed in that case.
+			"definition": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ConflictsWith:    []string{"policy_family_id"},
+				ValidateFunc:     validateJson,
+				DiffSuppressFunc: resourceDatabricksClusterPolicySuppressEquivalentJson,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"max_clusters_per_user": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"policy_family_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"policy_family_definition_overrides": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validateJson,
+				DiffSuppressFunc: resourceDatabricksClusterPolicySuppressEquivalentJson,
+			},
+		},
+	}
+}
+
+func resourceDatabricksClusterPolicyCreate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).clusterPolicies
+
+	log.Print("[DEBUG] Creating cluster policy")
+
+	resp, err := apiClient.Create(resourceDatabricksClusterPolicyExpand(d))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resp.PolicyId)
+
+	log.Printf("[DEBUG] Cluster policy ID: %s", d.Id())
+
+	return resourceDatabricksClusterPolicyRead(d, m)
+}
+
+func resourceDatabricksClusterPolicyRead(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).clusterPolicies
+
+	resp, err := apiClient.Get(&clusterPoliciesIdRequest{
+		PolicyId: d.Id(),
+	})
+	if err != nil {
+		if resourceDatabricksClusterPolicyNotExistsError(err) {
+			log.Printf("[WARN] Cluster policy (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("definition", resp.Definition)
+	d.Set("description", resp.Description)
+	d.Set("max_clusters_per_user", resp.MaxClustersPerUser)
+	d.Set("policy_family_id", resp.PolicyFamilyId)
+	d.Set("policy_family_definition_overrides", resp.PolicyFamilyDefinitionOverrides)
+
+	return nil
+}
+
+func resourceDatabricksClusterPolicyUpdate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).clusterPolicies
+
+	log.Printf("[DEBUG] Updating cluster policy: %s", d.Id())
+
+	request := resourceDatabricksClusterPolicyExpand(d)
+	request.PolicyId = d.Id()
+
+	err := apiClient.Edit(request)
+	if err != nil {
+		return err
+	}
+
+	return resourceDatabricksClusterPolicyRead(d, m)
+}
+
+func resourceDatabricksClusterPolicyDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).clusterPolicies
+
+	log.Printf("[DEBUG] Deleting cluster policy: %s", d.Id())
+
+	err := apiClient.Delete(&clusterPoliciesIdRequest{
+		PolicyId: d.Id(),
+	})
+	if err != nil && !resourceDatabricksClusterPolicyNotExistsError(err) {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// resourceDatabricksClusterPolicyExpand builds the policy from the
+// configuration. The definition of policies based on a family is not sent,
+// as it is computed from the family and its overrides.
+func resourceDatabricksClusterPolicyExpand(d *schema.ResourceData) *clusterPolicy {
+	policy := &clusterPolicy{
+		Name:                            d.Get("name").(string),
+		Description:                     d.Get("description").(string),
+		MaxClustersPerUser:              int64(d.Get("max_clusters_per_user").(int)),
+		PolicyFamilyId:                  d.Get("policy_family_id").(string),
+		PolicyFamilyDefinitionOverrides: d.Get("policy_family_definition_overrides").(string),
+	}
+
+	if policy.PolicyFamilyId == "" {
+		policy.Definition = d.Get("definition").(string)
+	}
+
+	return policy
+}
+
+// resourceDatabricksClusterPolicySuppressEquivalentJson ignores differences in
+// formatting and key order between JSON documents.
+func resourceDatabricksClusterPolicySuppressEquivalentJson(k, old, new string, d *schema.ResourceData) bool {
+	var oldValue, newValue interface{}
+
+	if err := json.Unmarshal([]byte(old), &oldValue); err != nil {
+		return false
+	}
+
+	if err := json.Unmarshal([]byte(new), &newValue); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(oldValue, newValue)
+}
+
+func resourceDatabricksClusterPolicyNotExistsError(err error) bool {
+	databricksError, ok := err.(client.Error)
+	return ok && databricksError.Code() == "RESOURCE_DOES_NOT_EXIST"
+}
@@ -0,0 +1,165 @@
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"testing"
+)
+
+func TestAccDatabricksClusterPolicy_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksClusterPolicyDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksClusterPolicyConfig(1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksClusterPolicyExists("databricks_cluster_policy.policy"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster_policy.policy", "name", "tf-test-policy"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster_policy.policy", "max_clusters_per_user", "1"),
+				),
+			},
+			{
+				Config: testAccDatabricksClusterPolicyConfig(2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksClusterPolicyExists("databricks_cluster_policy.policy"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster_policy.policy", "max_clusters_per_user", "2"),
+				),
+			},
+			{
+				ResourceName:      "databricks_cluster_policy.policy",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksClusterPolicyExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*Client).clusterPolicies
+
+		_, err := conn.Get(&clusterPoliciesIdRequest{
+			PolicyId: rs.Primary.ID,
+		})
+
+		return err
+	}
+}
+
+func testAccCheckDatabricksClusterPolicyDestroy(s *terraform.State) error {
+	endpoint := testAccProvider.Meta().(*Client).clusterPolicies
+
+	_, err := endpoint.Get(&clusterPoliciesIdRequest{
+		PolicyId: s.RootModule().Resources["databricks_cluster_policy.policy"].Primary.ID,
+	})
+	if err == nil {
+		return errors.New("cluster policy still exists")
+	}
+
+	if !resourceDatabricksClusterPolicyNotExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func testAccDatabricksClusterPolicyConfig(maxClustersPerUser int) string {
+	return fmt.Sprintf(`
+resource "databricks_cluster_policy" "policy" {
+	name                  = "tf-test-policy"
+	max_clusters_per_user = %d
+
+	definition = <<EOF
+{
+	"node_type_id": {
+		"type": "allowlist",
+		"values": ["Standard_D3_v2", "Standard_D4_v2"]
+	},
+	"dbus_per_hour": {
+		"type": "range",
+		"maxValue": 10
+	}
+}
+EOF
+}
+`, maxClustersPerUser)
+}
+
+func TestDatabricksClusterPolicy_suppressesEquivalentJson(t *testing.T) {
+	old := `{"node_type_id":{"type":"fixed","value":"m4.large"},"autotermination_minutes":{"type":"fixed","value":30}}`
+
+	cases := []struct {
+		new      string
+		suppress bool
+	}{
+		{
+			new: `{
+				"autotermination_minutes": {"value": 30, "type": "fixed"},
+				"node_type_id": {"type": "fixed", "value": "m4.large"}
+			}`,
+			suppress: true,
+		},
+		{
+			new:      `{"node_type_id":{"type":"fixed","value":"m4.xlarge"},"autotermination_minutes":{"type":"fixed","value":30}}`,
+			suppress: false,
+		},
+		{
+			new:      `{"node_type_id":`,
+			suppress: false,
+		},
+	}
+
+	for _, c := range cases {
+		if resourceDatabricksClusterPolicySuppressEquivalentJson("definition", old, c.new, nil) != c.suppress {
+			t.Fatalf("Wrong result for %s: expected %t", c.new, c.suppress)
+		}
+	}
+}
+
+func TestDatabricksClusterPolicy_validatesDefinition(t *testing.T) {
+	_, errs := validateJson(`{"node_type_id": {"type": "fixed"`, "definition")
+	if len(errs) == 0 {
+		t.Fatal("No error was returned for an invalid definition")
+	}
+
+	_, errs = validateJson(`{"node_type_id": {"type": "fixed", "value": "m4.large"}}`, "definition")
+	if len(errs) > 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+}
+
+func TestDatabricksClusterPolicy_expandOmitsFamilyDefinition(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksClusterPolicy().Schema, map[string]interface{}{
+		"name":                               "tf-test-policy",
+		"policy_family_id":                   "personal-vm",
+		"policy_family_definition_overrides": `{"autotermination_minutes": {"type": "fixed", "value": 30}}`,
+	})
+	d.Set("definition", `{"node_type_id": {"type": "fixed", "value": "m4.large"}}`)
+
+	policy := resourceDatabricksClusterPolicyExpand(d)
+
+	if policy.Definition != "" {
+		t.Fatalf("Definition was sent for a family policy: %s", policy.Definition)
+	}
+
+	if policy.PolicyFamilyId != "personal-vm" || policy.PolicyFamilyDefinitionOverrides == "" {
+		t.Fatalf("Wrong policy: %+v", policy)
+	}
+}
@@ -1,13 +1,18 @@
 package databricks
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/betabandido/databricks-sdk-go/client"
 	"github.com/betabandido/databricks-sdk-go/models"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestAccDatabricksCluster_basic(t *testing.T) {
@@ -30,6 +35,12 @@ func TestAccDatabricksCluster_basic(t *testing.T) {
 						"databricks_cluster.cluster", "num_workers", "1"),
 					resource.TestCheckResourceAttr(
 						"databricks_cluster.cluster", "autotermination_minutes", "10"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "state", "RUNNING"),
+					resource.TestCheckResourceAttrSet(
+						"databricks_cluster.cluster", "driver_private_ip"),
+					resource.TestCheckResourceAttrSet(
+						"databricks_cluster.cluster", "url"),
 				),
 			},
 			{
@@ -46,8 +57,28 @@ func TestAccDatabricksCluster_basic(t *testing.T) {
 						"databricks_cluster.cluster", "num_workers", "2"),
 					resource.TestCheckResourceAttr(
 						"databricks_cluster.cluster", "autotermination_minutes", "15"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "spark_conf.spark.speculation", "true"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "spark_env_vars.PYSPARK_PYTHON", "/databricks/python3/bin/python3"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "custom_tags.%", "1"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "custom_tags.CostCenter", "tf-test"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "cluster_log_conf.0.dbfs.0.destination", "dbfs:/tmp/tf-test/logs"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "init_scripts.#", "1"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "init_scripts.0.dbfs.0.destination", "dbfs:/tmp/tf-test/init.sh"),
 				),
 			},
+			{
+				ResourceName:            "databricks_cluster.cluster",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"permanently_delete", "idempotency_token"},
+			},
 		},
 	})
 }
@@ -65,7 +96,7 @@ func testAccCheckDatabricksClusterExists(n string) resource.TestCheckFunc {
 
 		conn := testAccProvider.Meta().(*Client).clusters
 
-		_, err := conn.Get(&models.ClustersGetRequest{
+		_, err := conn.Get(&clustersIdRequest{
 			ClusterId: rs.Primary.ID,
 		})
 		if err != nil {
@@ -81,7 +112,7 @@ func testAccCheckDatabricksClusterDestroy(s *terraform.State) error {
 
 	clusterId := s.RootModule().Resources["databricks_cluster.cluster"].Primary.ID
 
-	_, err := endpoint.Get(&models.ClustersGetRequest{
+	_, err := endpoint.Get(&clustersIdRequest{
 		ClusterId: clusterId,
 	})
 
@@ -105,6 +136,10 @@ resource "databricks_cluster" "cluster" {
 	num_workers             = 1
 	autotermination_minutes = 10
 	permanently_delete      = true
+
+	timeouts {
+		create = "45m"
+	}
 } 
 `
 }
@@ -118,6 +153,30 @@ resource "databricks_cluster" "cluster" {
 	num_workers             = 2
 	autotermination_minutes = 15
 	permanently_delete      = true
+
+	spark_conf {
+		"spark.speculation" = "true"
+	}
+
+	spark_env_vars {
+		PYSPARK_PYTHON = "/databricks/python3/bin/python3"
+	}
+
+	custom_tags {
+		CostCenter = "tf-test"
+	}
+
+	cluster_log_conf {
+		dbfs {
+			destination = "dbfs:/tmp/tf-test/logs"
+		}
+	}
+
+	init_scripts {
+		dbfs {
+			destination = "dbfs:/tmp/tf-test/init.sh"
+		}
+	}
 } 
 `
 }
@@ -137,3 +196,1303 @@ func TestDatabricksCluster_handlesNonExistingClusterError(t *testing.T) {
 		t.Fatal("A non-existing-cluster error was not detected")
 	}
 }
+
+func TestDatabricksCluster_removesDefaultTags(t *testing.T) {
+	tags := map[string]string{
+		"CostCenter":  "tf-test",
+		"Vendor":      "Databricks",
+		"ClusterName": "tf-test-cluster",
+		"Creator":     "someone-else@example.com",
+	}
+
+	defaultTags := map[string]string{
+		"Vendor":      "Databricks",
+		"ClusterName": "tf-test-cluster",
+		"Creator":     "user@example.com",
+	}
+
+	configured := map[string]interface{}{
+		"CostCenter":  "tf-test",
+		"ClusterName": "tf-test-cluster",
+	}
+
+	result := resourceDatabricksClusterRemoveDefaultTags(tags, defaultTags, configured)
+
+	expected := map[string]string{
+		"CostCenter":  "tf-test",
+		"ClusterName": "tf-test-cluster",
+		"Creator":     "someone-else@example.com",
+	}
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("Wrong tags: %v", result)
+	}
+}
+
+func TestDatabricksCluster_expandsLogConfAndInitScripts(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksClusterSchema(), map[string]interface{}{
+		"spark_version": "4.2.x-scala2.11",
+		"node_type_id":  "Standard_D3_v2",
+		"num_workers":   1,
+		"cluster_log_conf": []interface{}{
+			map[string]interface{}{
+				"s3": []interface{}{
+					map[string]interface{}{
+						"destination":       "s3://logs/tf-test",
+						"region":            "eu-west-1",
+						"enable_encryption": true,
+						"encryption_type":   "sse-s3",
+						"canned_acl":        "bucket-owner-full-control",
+					},
+				},
+			},
+		},
+		"init_scripts": []interface{}{
+			map[string]interface{}{
+				"dbfs": []interface{}{
+					map[string]interface{}{
+						"destination": "dbfs:/tmp/tf-test/init.sh",
+					},
+				},
+			},
+			map[string]interface{}{
+				"workspace": []interface{}{
+					map[string]interface{}{
+						"destination": "/Users/user@example.com/init.sh",
+					},
+				},
+			},
+		},
+	})
+
+	spec := resourceDatabricksClusterExpandSpec(resourceDatabricksClusterSpecData(d))
+
+	expectedLogConf := &clusterLogConf{
+		S3: &clusterS3StorageInfo{
+			Destination:      "s3://logs/tf-test",
+			Region:           "eu-west-1",
+			EnableEncryption: true,
+			EncryptionType:   "sse-s3",
+			CannedAcl:        "bucket-owner-full-control",
+		},
+	}
+
+	if !reflect.DeepEqual(spec.ClusterLogConf, expectedLogConf) {
+		t.Fatalf("Wrong log configuration: %+v", spec.ClusterLogConf)
+	}
+
+	expectedInitScripts := []clusterInitScriptInfo{
+		{Dbfs: &clusterDbfsStorageInfo{Destination: "dbfs:/tmp/tf-test/init.sh"}},
+		{Workspace: &clusterWorkspaceStorageInfo{Destination: "/Users/user@example.com/init.sh"}},
+	}
+
+	if !reflect.DeepEqual(spec.InitScripts, expectedInitScripts) {
+		t.Fatalf("Wrong init scripts: %+v", spec.InitScripts)
+	}
+
+	flattened := resourceDatabricksClusterFlattenSpec(&spec)
+
+	if !reflect.DeepEqual(
+		resourceDatabricksClusterExpandClusterLogConf(testDatabricksClusterToList(flattened["cluster_log_conf"])),
+		expectedLogConf,
+	) {
+		t.Fatal("Log configuration does not round-trip")
+	}
+
+	if !reflect.DeepEqual(
+		resourceDatabricksClusterExpandInitScripts(testDatabricksClusterToList(flattened["init_scripts"])),
+		expectedInitScripts,
+	) {
+		t.Fatal("Init scripts do not round-trip")
+	}
+}
+
+func TestDatabricksCluster_omitsEmptyLogConf(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksClusterSchema(), map[string]interface{}{
+		"spark_version": "4.2.x-scala2.11",
+		"node_type_id":  "Standard_D3_v2",
+		"num_workers":   1,
+	})
+
+	spec := resourceDatabricksClusterExpandSpec(resourceDatabricksClusterSpecData(d))
+
+	if spec.ClusterLogConf != nil || len(spec.InitScripts) != 0 {
+		t.Fatalf("Unexpected log configuration or init scripts: %+v", spec)
+	}
+}
+
+// testDatabricksClusterToList converts the output of a flattener into the
+// form expanders receive.
+func testDatabricksClusterToList(flattened interface{}) []interface{} {
+	var result []interface{}
+	for _, elem := range flattened.([]map[string]interface{}) {
+		m := make(map[string]interface{}, len(elem))
+		for k, v := range elem {
+			if nested, ok := v.([]map[string]interface{}); ok {
+				v = testDatabricksClusterToList(nested)
+			}
+			m[k] = v
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+func TestDatabricksCluster_stateErrorIncludesTerminationReason(t *testing.T) {
+	code := models.CLOUD_PROVIDER_LAUNCH_FAILURE
+
+	err := resourceDatabricksClusterStateError(&clusterInfo{
+		ClusterId:    "0123-456789-abc123",
+		State:        models.TERMINATED,
+		StateMessage: "Instances could not be launched",
+		TerminationReason: &models.ClustersTerminationReason{
+			Code: &code,
+			Parameters: map[string]string{
+				"azure_error_code":    "OperationNotAllowed",
+				"azure_error_message": "Quota exceeded",
+			},
+		},
+	})
+
+	expected := "unexpected state (TERMINATED) for cluster 0123-456789-abc123: " +
+		"Instances could not be launched " +
+		"(termination code: CLOUD_PROVIDER_LAUNCH_FAILURE, " +
+		"parameters: azure_error_code=OperationNotAllowed, azure_error_message=Quota exceeded)"
+
+	if err.Error() != expected {
+		t.Fatalf("Wrong error: %s", err)
+	}
+}
+
+func TestDatabricksCluster_stateErrorWithoutTerminationReason(t *testing.T) {
+	err := resourceDatabricksClusterStateError(&clusterInfo{
+		ClusterId: "0123-456789-abc123",
+		State:     models.ERROR_,
+	})
+
+	if err.Error() != "unexpected state (ERROR) for cluster 0123-456789-abc123" {
+		t.Fatalf("Wrong error: %s", err)
+	}
+}
+
+func TestDatabricksCluster_waitForChangeWaitsForTransition(t *testing.T) {
+	// The cluster is still seen running right after the edit.
+	states := []models.ClustersClusterState{models.RUNNING, models.RESTARTING, models.RUNNING}
+	gets := 0
+
+	handler := func(path string, body []byte) interface{} {
+		info := testDatabricksClusterInfo(states[gets])
+		if gets < len(states)-1 {
+			gets++
+		}
+		return info
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	err := resourceDatabricksClusterWaitForChange(&clustersEndpoint{Client: cl}, "0123-456789-abc123", []models.ClustersClusterState{
+		models.RESTARTING,
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if gets != len(states)-1 {
+		t.Fatalf("Wait returned before the cluster restarted: %d polls", gets)
+	}
+}
+
+// testDatabricksClusterApi fakes the Clusters and Libraries APIs for a single
+// cluster, recording the operations (other than get and cluster-status) and the
+// edit and resize requests it receives. Running clusters that are edited or
+// resized are seen changing once. Libraries are installed immediately.
+type testDatabricksClusterApi struct {
+	info      clusterInfo
+	libraries []libraryFullStatus
+	calls     []string
+	edits     []clustersEditRequest
+	resizes   []clustersResizeRequest
+}
+
+func (a *testDatabricksClusterApi) handle(path string, body []byte) interface{} {
+	switch path {
+	case "clusters/get":
+		info := a.info
+		if info.State == models.RESTARTING || info.State == models.RESIZING {
+			a.info.State = models.RUNNING
+		}
+		return info
+	case "libraries/cluster-status":
+		return librariesClusterStatusResponse{
+			ClusterId:       a.info.ClusterId,
+			LibraryStatuses: a.libraries,
+		}
+	}
+
+	a.calls = append(a.calls, path)
+
+	switch path {
+	case "clusters/edit":
+		request := clustersEditRequest{}
+		json.Unmarshal(body, &request)
+		a.edits = append(a.edits, request)
+		a.info.clusterSpec = request.clusterSpec
+		if a.info.State == models.RUNNING {
+			a.info.State = models.RESTARTING
+		}
+	case "clusters/resize":
+		request := clustersResizeRequest{}
+		json.Unmarshal(body, &request)
+		a.resizes = append(a.resizes, request)
+		a.info.State = models.RESIZING
+	case "clusters/start":
+		a.info.State = models.RUNNING
+	case "clusters/delete":
+		a.info.State = models.TERMINATED
+	case "libraries/install":
+		request := librariesRequest{}
+		json.Unmarshal(body, &request)
+		for i := range request.Libraries {
+			a.libraries = append(a.libraries, libraryFullStatus{
+				Library: &request.Libraries[i],
+				Status:  "INSTALLED",
+			})
+		}
+	case "libraries/uninstall":
+		request := librariesRequest{}
+		json.Unmarshal(body, &request)
+		for _, l := range request.Libraries {
+			for i, status := range a.libraries {
+				if resourceDatabricksClusterLibraryKey(*status.Library) == resourceDatabricksClusterLibraryKey(l) {
+					a.libraries[i].Status = "UNINSTALL_ON_RESTART"
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// testDatabricksClusterUpdate applies the given configuration to a cluster
+// whose state has the given attributes.
+func testDatabricksClusterUpdate(
+	t *testing.T,
+	api *testDatabricksClusterApi,
+	attributes map[string]string,
+	raw map[string]interface{},
+) {
+	state := &terraform.InstanceState{
+		ID:         api.info.ClusterId,
+		Attributes: attributes,
+	}
+
+	testDatabricksClusterApply(t, api.handle, state, raw)
+}
+
+// testDatabricksClusterApply applies the given configuration to a cluster
+// with the given state (nil for new clusters), using handler to fake the API.
+func testDatabricksClusterApply(
+	t *testing.T,
+	handler testDatabricksHandler,
+	state *terraform.InstanceState,
+	raw map[string]interface{},
+) *terraform.InstanceState {
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	r := resourceDatabricksCluster()
+
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	newState, err := r.Apply(state, diff, testDatabricksClusterClient(cl))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	return newState
+}
+
+func testDatabricksClusterClient(cl *client.Client) *Client {
+	return &Client{
+		clusters:  &clustersEndpoint{Client: cl},
+		libraries: &librariesEndpoint{Client: cl},
+	}
+}
+
+func testDatabricksClusterAttributes() map[string]string {
+	return map[string]string{
+		"id":                      "0123-456789-abc123",
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             "1",
+		"autotermination_minutes": "10",
+		"permanently_delete":      "false",
+	}
+}
+
+func testDatabricksClusterInfo(state models.ClustersClusterState) clusterInfo {
+	return clusterInfo{
+		ClusterId: "0123-456789-abc123",
+		clusterSpec: clusterSpec{
+			ClusterName:            "tf-test-cluster",
+			SparkVersion:           "4.2.x-scala2.11",
+			NodeTypeId:             "Standard_D3_v2",
+			NumWorkers:             1,
+			AutoterminationMinutes: 10,
+		},
+		State: state,
+	}
+}
+
+func TestDatabricksCluster_updateEditsTerminatedCluster(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.TERMINATED),
+	}
+
+	testDatabricksClusterUpdate(t, api, testDatabricksClusterAttributes(), map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             2,
+		"autotermination_minutes": 10,
+		"aws_attributes": []interface{}{
+			map[string]interface{}{
+				"zone_id": "us-west-2a",
+			},
+		},
+	})
+
+	if len(api.edits) != 1 {
+		t.Fatalf("Wrong number of edits: %d", len(api.edits))
+	}
+
+	edit := api.edits[0]
+
+	if edit.ClusterId != "0123-456789-abc123" || edit.NumWorkers != 2 {
+		t.Fatalf("Wrong edit request: %+v", edit)
+	}
+
+	// Unchanged attributes must be sent too, as edits replace the whole spec.
+	if edit.ClusterName != "tf-test-cluster" || edit.AutoterminationMinutes != 10 {
+		t.Fatalf("Unchanged attributes were not sent: %+v", edit)
+	}
+
+	if edit.AwsAttributes == nil || edit.AwsAttributes.ZoneId != "us-west-2a" {
+		t.Fatalf("Wrong AWS attributes: %+v", edit.AwsAttributes)
+	}
+}
+
+func TestDatabricksCluster_updateEditsRunningCluster(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+	}
+
+	testDatabricksClusterUpdate(t, api, testDatabricksClusterAttributes(), map[string]interface{}{
+		"name":                    "tf-test-cluster-renamed",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             1,
+		"autotermination_minutes": 10,
+	})
+
+	if !reflect.DeepEqual(api.calls, []string{"clusters/edit"}) || api.edits[0].ClusterName != "tf-test-cluster-renamed" {
+		t.Fatalf("Wrong edit requests: %+v", api.edits)
+	}
+}
+
+func TestDatabricksCluster_updateSkipsEditWithoutClusterChanges(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+	}
+
+	testDatabricksClusterUpdate(t, api, testDatabricksClusterAttributes(), map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             1,
+		"autotermination_minutes": 10,
+		"permanently_delete":      true,
+	})
+
+	if len(api.edits) != 0 {
+		t.Fatalf("Cluster was edited: %+v", api.edits)
+	}
+}
+
+func TestDatabricksCluster_updateTerminatesClusterWhenDesired(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+	}
+
+	attributes := testDatabricksClusterAttributes()
+	attributes["desired_state"] = "RUNNING"
+
+	testDatabricksClusterUpdate(t, api, attributes, map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             1,
+		"autotermination_minutes": 10,
+		"desired_state":           "TERMINATED",
+	})
+
+	if !reflect.DeepEqual(api.calls, []string{"clusters/delete"}) {
+		t.Fatalf("Wrong calls: %v", api.calls)
+	}
+}
+
+func TestDatabricksCluster_updateEditsBeforeStartingCluster(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.TERMINATED),
+	}
+
+	attributes := testDatabricksClusterAttributes()
+	attributes["desired_state"] = "TERMINATED"
+
+	testDatabricksClusterUpdate(t, api, attributes, map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             2,
+		"autotermination_minutes": 10,
+		"desired_state":           "RUNNING",
+	})
+
+	if !reflect.DeepEqual(api.calls, []string{"clusters/edit", "clusters/start"}) {
+		t.Fatalf("Wrong calls: %v", api.calls)
+	}
+}
+
+func TestDatabricksCluster_reconcileStopsAtDeadline(t *testing.T) {
+	handler := func(path string, body []byte) interface{} {
+		return testDatabricksClusterInfo(models.PENDING)
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	// The deadline has been used up by the previous steps.
+	err := resourceDatabricksClusterReconcileState(&clustersEndpoint{Client: cl}, "0123-456789-abc123", "RUNNING", time.Now())
+	if _, ok := err.(*resource.TimeoutError); !ok {
+		t.Fatalf("Expected timeout error, got: %v", err)
+	}
+}
+
+func TestDatabricksCluster_stableState(t *testing.T) {
+	cases := map[models.ClustersClusterState]string{
+		models.PENDING:     "RUNNING",
+		models.RUNNING:     "RUNNING",
+		models.RESTARTING:  "RUNNING",
+		models.RESIZING:    "RUNNING",
+		models.TERMINATING: "TERMINATED",
+		models.TERMINATED:  "TERMINATED",
+		models.ERROR_:      "ERROR",
+	}
+
+	for state, expected := range cases {
+		if actual := resourceDatabricksClusterStableState(state); actual != expected {
+			t.Errorf("Wrong stable state for %s: %s", state, actual)
+		}
+	}
+}
+
+func TestDatabricksCluster_readsRuntimeAttributes(t *testing.T) {
+	info := testDatabricksClusterInfo(models.RUNNING)
+	info.Driver = &models.ClustersSparkNode{PrivateIp: "10.0.0.4"}
+	info.JdbcPort = 10000
+	info.SparkContextId = 8734983498349834983
+	info.CreatorUserName = "user@example.com"
+	info.ClusterMemoryMb = 28672
+	info.ClusterCores = 8
+	info.StartTime = 1533818263591
+	info.DefaultTags = map[string]string{"Vendor": "Databricks"}
+
+	api := &testDatabricksClusterApi{info: info}
+
+	cl, closeServer := testDatabricksServer(t, api.handle)
+	defer closeServer()
+
+	state, err := resourceDatabricksCluster().Refresh(
+		&terraform.InstanceState{
+			ID:         info.ClusterId,
+			Attributes: testDatabricksClusterAttributes(),
+		},
+		&Client{
+			domain:    "example.cloud.databricks.com",
+			clusters:  &clustersEndpoint{Client: cl},
+			libraries: &librariesEndpoint{Client: cl},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"state":             "RUNNING",
+		"driver_private_ip": "10.0.0.4",
+		"jdbc_port":         "10000",
+		"spark_context_id":  "8734983498349834983",
+		"creator_user_name": "user@example.com",
+		"cluster_memory_mb": "28672",
+		"cluster_cores":     "8",
+		"start_time":        "1533818263591",
+		"default_tags.%":    "1",
+		"url":               "https://example.cloud.databricks.com/#setting/clusters/0123-456789-abc123/configuration",
+	}
+
+	for k, v := range expected {
+		if state.Attributes[k] != v {
+			t.Errorf("Wrong value for %s: %s", k, state.Attributes[k])
+		}
+	}
+}
+
+func TestDatabricksCluster_updateResizesRunningCluster(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+	}
+
+	testDatabricksClusterUpdate(t, api, testDatabricksClusterAttributes(), map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             3,
+		"autotermination_minutes": 10,
+	})
+
+	if !reflect.DeepEqual(api.calls, []string{"clusters/resize"}) {
+		t.Fatalf("Wrong calls: %v", api.calls)
+	}
+
+	resize := api.resizes[0]
+	if resize.NumWorkers == nil || *resize.NumWorkers != 3 || resize.Autoscale != nil {
+		t.Fatalf("Wrong resize request: %+v", resize)
+	}
+}
+
+func TestDatabricksCluster_updateResizesToAutoscale(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+	}
+
+	attributes := testDatabricksClusterAttributes()
+	delete(attributes, "num_workers")
+
+	testDatabricksClusterUpdate(t, api, attributes, map[string]interface{}{
+		"name":          "tf-test-cluster",
+		"spark_version": "4.2.x-scala2.11",
+		"node_type_id":  "Standard_D3_v2",
+		"autoscale": []interface{}{
+			map[string]interface{}{
+				"min_workers": 1,
+				"max_workers": 4,
+			},
+		},
+		"autotermination_minutes": 10,
+	})
+
+	if !reflect.DeepEqual(api.calls, []string{"clusters/resize"}) {
+		t.Fatalf("Wrong calls: %v", api.calls)
+	}
+
+	resize := api.resizes[0]
+	if resize.NumWorkers != nil || resize.Autoscale == nil || resize.Autoscale.MaxWorkers != 4 {
+		t.Fatalf("Wrong resize request: %+v", resize)
+	}
+}
+
+func TestDatabricksCluster_updateEditsWhenResizingWithOtherChanges(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+	}
+
+	testDatabricksClusterUpdate(t, api, testDatabricksClusterAttributes(), map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             3,
+		"autotermination_minutes": 20,
+	})
+
+	if !reflect.DeepEqual(api.calls, []string{"clusters/edit"}) {
+		t.Fatalf("Wrong calls: %v", api.calls)
+	}
+}
+
+func TestDatabricksCluster_createRetriesWithSameIdempotencyToken(t *testing.T) {
+	var tokens []string
+	created := 0
+
+	handler := func(path string, body []byte) interface{} {
+		switch path {
+		case "clusters/create":
+			request := clustersCreateRequest{}
+			json.Unmarshal(body, &request)
+			tokens = append(tokens, request.IdempotencyToken)
+
+			// The first request creates the cluster, but fails as if the
+			// response had been lost in a gateway.
+			if len(tokens) == 1 {
+				created++
+				return testDatabricksErrorResponse{
+					StatusCode: 504,
+					ErrorCode:  "TEMPORARILY_UNAVAILABLE",
+					Message:    "Gateway timeout",
+				}
+			}
+
+			return clustersCreateResponse{ClusterId: "0123-456789-abc123"}
+		case "clusters/get":
+			return testDatabricksClusterInfo(models.RUNNING)
+		}
+		return nil
+	}
+
+	state := testDatabricksClusterApply(t, handler, nil, map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             1,
+		"autotermination_minutes": 10,
+	})
+
+	if created != 1 || len(tokens) != 2 {
+		t.Fatalf("Wrong number of requests: %d clusters created, %d requests", created, len(tokens))
+	}
+
+	if tokens[0] == "" || tokens[0] != tokens[1] {
+		t.Fatalf("Wrong idempotency tokens: %v", tokens)
+	}
+
+	if state.ID != "0123-456789-abc123" || state.Attributes["idempotency_token"] != tokens[0] {
+		t.Fatalf("Wrong state: %+v", state)
+	}
+}
+
+func TestDatabricksCluster_createUsesUniqueIdempotencyTokens(t *testing.T) {
+	var tokens []string
+
+	handler := func(path string, body []byte) interface{} {
+		switch path {
+		case "clusters/create":
+			request := clustersCreateRequest{}
+			json.Unmarshal(body, &request)
+			tokens = append(tokens, request.IdempotencyToken)
+
+			return clustersCreateResponse{ClusterId: fmt.Sprintf("0123-456789-abc12%d", len(tokens))}
+		case "clusters/get":
+			return testDatabricksClusterInfo(models.RUNNING)
+		}
+		return nil
+	}
+
+	// Identical clusters (e.g., created with count) must not be taken for
+	// the same cluster.
+	for i := 0; i < 2; i++ {
+		testDatabricksClusterApply(t, handler, nil, map[string]interface{}{
+			"name":                    "tf-test-cluster",
+			"spark_version":           "4.2.x-scala2.11",
+			"node_type_id":            "Standard_D3_v2",
+			"num_workers":             1,
+			"autotermination_minutes": 10,
+		})
+	}
+
+	if len(tokens) != 2 || tokens[0] == "" || tokens[0] == tokens[1] {
+		t.Fatalf("Wrong idempotency tokens: %v", tokens)
+	}
+}
+
+func TestDatabricksCluster_importSkipsLibraries(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksCluster().Schema, map[string]interface{}{})
+	d.SetId("0123-456789-abc123")
+
+	// No endpoint is set, so the import fails if it looks up the libraries.
+	_, err := resourceDatabricksClusterImport(d, &Client{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if d.Get("library").(*schema.Set).Len() > 0 {
+		t.Fatalf("Libraries were imported: %v", d.Get("library"))
+	}
+}
+
+func TestDatabricksCluster_migratesAwsAttributesSet(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "0123-456789-abc123",
+		Attributes: map[string]string{
+			"name":                              "tf-test-cluster",
+			"aws_attributes.#":                  "1",
+			"aws_attributes.2863404045.zone_id": "us-west-2a",
+			"aws_attributes.2863404045.ebs_volume_count": "1",
+		},
+	}
+
+	is, err := resourceDatabricksClusterMigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := map[string]string{
+		"name":                              "tf-test-cluster",
+		"aws_attributes.#":                  "1",
+		"aws_attributes.0.zone_id":          "us-west-2a",
+		"aws_attributes.0.ebs_volume_count": "1",
+	}
+	if !reflect.DeepEqual(is.Attributes, expected) {
+		t.Fatalf("Wrong attributes: %v", is.Attributes)
+	}
+}
+
+func TestDatabricksCluster_expandsAwsAttributes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksClusterSchema(), map[string]interface{}{
+		"spark_version": "4.2.x-scala2.11",
+		"node_type_id":  "m4.large",
+		"num_workers":   1,
+		"aws_attributes": []interface{}{
+			map[string]interface{}{
+				"availability":           "SPOT_WITH_FALLBACK",
+				"first_on_demand":        1,
+				"spot_bid_price_percent": 80,
+				"zone_id":                "auto",
+			},
+		},
+	})
+
+	spec := resourceDatabricksClusterExpandSpec(resourceDatabricksClusterSpecData(d))
+
+	availability := models.SPOT_WITH_FALLBACK
+	expected := &models.ClustersAwsAttributes{
+		Availability:        &availability,
+		FirstOnDemand:       1,
+		SpotBidPricePercent: 80,
+		ZoneId:              "auto",
+	}
+
+	// The EBS volume type must not be sent when it is not set.
+	if !reflect.DeepEqual(spec.AwsAttributes, expected) {
+		t.Fatalf("Wrong AWS attributes: %+v", spec.AwsAttributes)
+	}
+}
+
+func TestDatabricksCluster_validatesAwsAvailability(t *testing.T) {
+	validate := resourceDatabricksClusterSchema()["aws_attributes"].Elem.(*schema.Resource).Schema["availability"].ValidateFunc
+
+	if _, errs := validate("SPOT", "availability"); len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	if _, errs := validate("RESERVED", "availability"); len(errs) == 0 {
+		t.Fatal("No error was returned for an invalid availability")
+	}
+}
+
+func TestDatabricksCluster_suppressesAutoZone(t *testing.T) {
+	cases := []struct {
+		old      string
+		new      string
+		suppress bool
+	}{
+		{"us-west-2a", "auto", true},
+		{"us-west-2a", "us-west-2b", false},
+		{"", "auto", false},
+		{"auto", "us-west-2a", false},
+	}
+
+	for _, c := range cases {
+		if resourceDatabricksClusterSuppressAutoZone("aws_attributes.0.zone_id", c.old, c.new, nil) != c.suppress {
+			t.Errorf("Wrong result for %q => %q", c.old, c.new)
+		}
+	}
+}
+
+func TestDatabricksCluster_expandsAzureAttributes(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksClusterSchema(), map[string]interface{}{
+		"spark_version": "4.2.x-scala2.11",
+		"node_type_id":  "Standard_D3_v2",
+		"num_workers":   1,
+		"azure_attributes": []interface{}{
+			map[string]interface{}{
+				"availability":       "SPOT_WITH_FALLBACK_AZURE",
+				"first_on_demand":    1,
+				"spot_bid_max_price": -1,
+			},
+		},
+	})
+
+	spec := resourceDatabricksClusterExpandSpec(resourceDatabricksClusterSpecData(d))
+
+	expected := &clusterAzureAttributes{
+		FirstOnDemand:   1,
+		Availability:    "SPOT_WITH_FALLBACK_AZURE",
+		SpotBidMaxPrice: -1,
+	}
+
+	if !reflect.DeepEqual(spec.AzureAttributes, expected) {
+		t.Fatalf("Wrong Azure attributes: %+v", spec.AzureAttributes)
+	}
+
+	if spec.AwsAttributes != nil || spec.GcpAttributes != nil {
+		t.Fatalf("Unexpected attributes for other clouds: %+v", spec)
+	}
+}
+
+func TestDatabricksCluster_cloudAttributesConflict(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"spark_version": "4.2.x-scala2.11",
+		"node_type_id":  "Standard_D3_v2",
+		"num_workers":   1,
+		"aws_attributes": []interface{}{
+			map[string]interface{}{
+				"zone_id": "auto",
+			},
+		},
+		"gcp_attributes": []interface{}{
+			map[string]interface{}{
+				"availability": "PREEMPTIBLE_GCP",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, errs := resourceDatabricksCluster().Validate(terraform.NewResourceConfig(c))
+	if len(errs) == 0 {
+		t.Fatal("No error was returned for conflicting cloud attributes")
+	}
+}
+
+func TestDatabricksCluster_readKeepsDockerPassword(t *testing.T) {
+	info := testDatabricksClusterInfo(models.RUNNING)
+	info.DockerImage = &clusterDockerImage{
+		Url: "registry.example.com/runtime:latest",
+		BasicAuth: &clusterDockerBasicAuth{
+			Username: "robot",
+		},
+	}
+
+	api := &testDatabricksClusterApi{info: info}
+
+	cl, closeServer := testDatabricksServer(t, api.handle)
+	defer closeServer()
+
+	attributes := testDatabricksClusterAttributes()
+	attributes["docker_image.#"] = "1"
+	attributes["docker_image.0.url"] = "registry.example.com/runtime:latest"
+	attributes["docker_image.0.basic_auth.#"] = "1"
+	attributes["docker_image.0.basic_auth.0.username"] = "robot"
+	attributes["docker_image.0.basic_auth.0.password"] = "secret"
+
+	state, err := resourceDatabricksCluster().Refresh(
+		&terraform.InstanceState{
+			ID:         info.ClusterId,
+			Attributes: attributes,
+		},
+		testDatabricksClusterClient(cl),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if state.Attributes["docker_image.0.url"] != "registry.example.com/runtime:latest" {
+		t.Fatalf("Wrong URL: %s", state.Attributes["docker_image.0.url"])
+	}
+
+	if state.Attributes["docker_image.0.basic_auth.0.password"] != "secret" {
+		t.Fatalf("Password was not kept: %s", state.Attributes["docker_image.0.basic_auth.0.password"])
+	}
+}
+
+func TestDatabricksCluster_updateInstallsAndUninstallsLibraries(t *testing.T) {
+	api := &testDatabricksClusterApi{info: testDatabricksClusterInfo(models.RUNNING)}
+
+	state := &terraform.InstanceState{
+		ID:         api.info.ClusterId,
+		Attributes: testDatabricksClusterAttributes(),
+	}
+
+	raw := map[string]interface{}{
+		"name":                    "tf-test-cluster",
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"num_workers":             1,
+		"autotermination_minutes": 10,
+		"library": []interface{}{
+			map[string]interface{}{"jar": "dbfs:/jars/old.jar"},
+		},
+	}
+
+	// The first apply brings the library into the state.
+	state = testDatabricksClusterApply(t, api.handle, state, raw)
+	api.calls = nil
+
+	raw["library"] = []interface{}{
+		map[string]interface{}{
+			"pypi": []interface{}{
+				map[string]interface{}{"package": "simplejson==3.8.0"},
+			},
+		},
+	}
+
+	state = testDatabricksClusterApply(t, api.handle, state, raw)
+
+	if !reflect.DeepEqual(api.calls, []string{"libraries/uninstall", "libraries/install"}) {
+		t.Fatalf("Wrong calls: %v", api.calls)
+	}
+
+	if api.libraries[0].Status != "UNINSTALL_ON_RESTART" {
+		t.Fatalf("Library was not uninstalled: %+v", api.libraries[0])
+	}
+
+	if state.Attributes["library.#"] != "1" {
+		t.Fatalf("Wrong libraries in state: %v", state.Attributes)
+	}
+}
+
+func TestDatabricksCluster_readIgnoresUnmanagedLibraries(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+		libraries: []libraryFullStatus{
+			{Library: &library{Jar: "dbfs:/jars/managed.jar"}, Status: "INSTALLED"},
+			{Library: &library{Jar: "dbfs:/jars/other.jar"}, Status: "INSTALLED"},
+			{Library: &library{Egg: "dbfs:/eggs/global.egg"}, Status: "INSTALLED", IsLibraryForAllClusters: true},
+		},
+	}
+
+	cl, closeServer := testDatabricksServer(t, api.handle)
+	defer closeServer()
+
+	d := resourceDatabricksCluster().TestResourceData()
+	d.SetId(api.info.ClusterId)
+	d.Set("library", []interface{}{
+		map[string]interface{}{"jar": "dbfs:/jars/managed.jar"},
+		map[string]interface{}{"jar": "dbfs:/jars/removed.jar"},
+	})
+
+	err := resourceDatabricksClusterRead(d, testDatabricksClusterClient(cl))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	libraries, err := resourceDatabricksClusterExpandLibraries(d.Get("library").(*schema.Set).List())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(libraries, []library{{Jar: "dbfs:/jars/managed.jar"}}) {
+		t.Fatalf("Wrong libraries: %+v", libraries)
+	}
+}
+
+func TestDatabricksCluster_installFailsWithMessages(t *testing.T) {
+	handler := func(path string, body []byte) interface{} {
+		switch path {
+		case "clusters/get":
+			return testDatabricksClusterInfo(models.RUNNING)
+		case "libraries/cluster-status":
+			return librariesClusterStatusResponse{
+				LibraryStatuses: []libraryFullStatus{
+					{
+						Library: &library{Pypi: &libraryPythonPyPi{Package: "missing"}},
+						Status:  "FAILED",
+						Messages: []string{
+							"No matching distribution found for missing",
+						},
+					},
+				},
+			}
+		}
+		return nil
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	err := resourceDatabricksClusterInstallLibraries(
+		testDatabricksClusterClient(cl),
+		"0123-456789-abc123",
+		[]library{{Pypi: &libraryPythonPyPi{Package: "missing"}}},
+		time.Minute,
+	)
+
+	expected := "failed to install libraries on cluster 0123-456789-abc123: " +
+		"pypi missing (No matching distribution found for missing)"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Wrong error: %v", err)
+	}
+}
+
+func TestDatabricksCluster_installFailsForSkippedOrUninstallingLibraries(t *testing.T) {
+	handler := func(path string, body []byte) interface{} {
+		switch path {
+		case "clusters/get":
+			return testDatabricksClusterInfo(models.RUNNING)
+		case "libraries/cluster-status":
+			return librariesClusterStatusResponse{
+				LibraryStatuses: []libraryFullStatus{
+					{
+						Library:  &library{Jar: "dbfs:/jars/skipped.jar"},
+						Status:   "SKIPPED",
+						Messages: []string{"Incompatible Scala version"},
+					},
+					{
+						Library: &library{Jar: "dbfs:/jars/removed.jar"},
+						Status:  "UNINSTALL_ON_RESTART",
+					},
+				},
+			}
+		}
+		return nil
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	err := resourceDatabricksClusterInstallLibraries(
+		testDatabricksClusterClient(cl),
+		"0123-456789-abc123",
+		[]library{{Jar: "dbfs:/jars/skipped.jar"}, {Jar: "dbfs:/jars/removed.jar"}},
+		time.Minute,
+	)
+
+	expected := "failed to install libraries on cluster 0123-456789-abc123: " +
+		"jar dbfs:/jars/skipped.jar (Incompatible Scala version), " +
+		"jar dbfs:/jars/removed.jar (pending uninstall, the cluster must be restarted first)"
+	if err == nil || err.Error() != expected {
+		t.Fatalf("Wrong error: %v", err)
+	}
+}
+
+func TestDatabricksCluster_expandLibraryRequiresOneType(t *testing.T) {
+	_, err := resourceDatabricksClusterExpandLibraries([]interface{}{
+		map[string]interface{}{
+			"jar":   "dbfs:/jars/a.jar",
+			"egg":   "dbfs:/eggs/b.egg",
+			"whl":   "",
+			"pypi":  []interface{}{},
+			"maven": []interface{}{},
+			"cran":  []interface{}{},
+		},
+	})
+	if err == nil {
+		t.Fatal("No error was returned for a library with two types")
+	}
+}
+
+func TestDatabricksCluster_expandsInstancePool(t *testing.T) {
+	spec := resourceDatabricksClusterExpandSpec(map[string]interface{}{
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"driver_node_type_id":     "Standard_D3_v2",
+		"instance_pool_id":        "0123-456789-pool1",
+		"driver_instance_pool_id": "0123-456789-pool1",
+	})
+
+	if spec.NodeTypeId != "" || spec.DriverNodeTypeId != "" {
+		t.Fatalf("Node types were sent along with the pool: %+v", spec)
+	}
+
+	if spec.InstancePoolId != "0123-456789-pool1" || spec.DriverInstancePoolId != "0123-456789-pool1" {
+		t.Fatalf("Wrong pools: %+v", spec)
+	}
+}
+
+func TestDatabricksCluster_expandDropsDriverPoolWithoutPool(t *testing.T) {
+	spec := resourceDatabricksClusterExpandSpec(map[string]interface{}{
+		"spark_version":           "4.2.x-scala2.11",
+		"node_type_id":            "Standard_D3_v2",
+		"driver_node_type_id":     "Standard_D4_v2",
+		"instance_pool_id":        "",
+		"driver_instance_pool_id": "0123-456789-pool1",
+	})
+
+	if spec.DriverInstancePoolId != "" || spec.DriverNodeTypeId != "Standard_D4_v2" {
+		t.Fatalf("Stale driver pool was sent: %+v", spec)
+	}
+}
+
+func TestDatabricksCluster_diffRequiresNodeTypeOrPool(t *testing.T) {
+	cases := []struct {
+		raw   map[string]interface{}
+		valid bool
+	}{
+		{map[string]interface{}{"node_type_id": "Standard_D3_v2"}, true},
+		{map[string]interface{}{"instance_pool_id": "0123-456789-pool12"}, true},
+		{map[string]interface{}{"instance_pool_id": "${databricks_instance_pool.pool.id}"}, true},
+		{map[string]interface{}{}, false},
+	}
+
+	for _, c := range cases {
+		c.raw["spark_version"] = "4.2.x-scala2.11"
+		c.raw["num_workers"] = 1
+
+		cfg, err := config.NewRawConfig(c.raw)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		_, err = resourceDatabricksCluster().Diff(nil, terraform.NewResourceConfig(cfg), nil)
+		if (err == nil) != c.valid {
+			t.Fatalf("Wrong result for %v: %v", c.raw, err)
+		}
+	}
+}
+
+func TestDatabricksCluster_diffIgnoresNodeTypeOfPool(t *testing.T) {
+	cfg, err := config.NewRawConfig(map[string]interface{}{
+		"spark_version":    "4.2.x-scala2.11",
+		"instance_pool_id": "0123-456789-pool12",
+		"num_workers":      1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	diff, err := resourceDatabricksCluster().Diff(
+		&terraform.InstanceState{
+			ID: "0123-456789-abc123",
+			Attributes: map[string]string{
+				"spark_version":    "4.2.x-scala2.11",
+				"instance_pool_id": "0123-456789-pool12",
+				"node_type_id":     "Standard_D3_v2",
+				"num_workers":      "1",
+			},
+		},
+		terraform.NewResourceConfig(cfg),
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if diff != nil && diff.Attributes["node_type_id"] != nil {
+		t.Fatalf("Node type of the pool was not ignored: %v", diff.Attributes["node_type_id"])
+	}
+}
+
+func TestDatabricksCluster_validatesSingleUserName(t *testing.T) {
+	cases := []struct {
+		dataSecurityMode string
+		singleUserName   string
+		valid            bool
+	}{
+		{"SINGLE_USER", "user@example.com", true},
+		{"LEGACY_SINGLE_USER", "user@example.com", true},
+		{"SINGLE_USER", "", false},
+		{"USER_ISOLATION", "user@example.com", false},
+		{"USER_ISOLATION", "", true},
+		{"", "", true},
+	}
+
+	for _, c := range cases {
+		err := resourceDatabricksClusterValidateSingleUser(c.dataSecurityMode, c.singleUserName)
+		if (err == nil) != c.valid {
+			t.Fatalf("Wrong result for %q and %q: %v", c.dataSecurityMode, c.singleUserName, err)
+		}
+	}
+}
+
+func TestDatabricksCluster_diffRequiresSingleUserName(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"spark_version":      "4.2.x-scala2.11",
+		"node_type_id":       "Standard_D3_v2",
+		"num_workers":        1,
+		"data_security_mode": "SINGLE_USER",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = resourceDatabricksCluster().Diff(nil, terraform.NewResourceConfig(c), nil)
+	if err == nil {
+		t.Fatal("No error was returned for a single user cluster without user")
+	}
+}
+
+func TestDatabricksCluster_diffShowsUnconfiguredAccessModeChanges(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"spark_version": "4.2.x-scala2.11",
+		"node_type_id":  "Standard_D3_v2",
+		"num_workers":   1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	cases := []struct {
+		mode    string
+		changed bool
+	}{
+		{"USER_ISOLATION", true},
+		{"NONE", false},
+	}
+
+	for _, m := range cases {
+		diff, err := resourceDatabricksCluster().Diff(
+			&terraform.InstanceState{
+				ID: "0123-456789-abc123",
+				Attributes: map[string]string{
+					"spark_version":      "4.2.x-scala2.11",
+					"node_type_id":       "Standard_D3_v2",
+					"num_workers":        "1",
+					"data_security_mode": m.mode,
+				},
+			},
+			terraform.NewResourceConfig(c),
+			nil,
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		changed := diff != nil && diff.Attributes["data_security_mode"] != nil
+		if changed != m.changed {
+			t.Fatalf("Wrong diff for %s: %v", m.mode, diff)
+		}
+	}
+}
+
+func TestDatabricksCluster_readDetectsAccessModeChanges(t *testing.T) {
+	info := testDatabricksClusterInfo(models.TERMINATED)
+	info.DataSecurityMode = "USER_ISOLATION"
+	info.RuntimeEngine = "PHOTON"
+
+	api := &testDatabricksClusterApi{info: info}
+
+	cl, closeServer := testDatabricksServer(t, api.handle)
+	defer closeServer()
+
+	attributes := testDatabricksClusterAttributes()
+	attributes["data_security_mode"] = "SINGLE_USER"
+	attributes["single_user_name"] = "user@example.com"
+	attributes["runtime_engine"] = "STANDARD"
+
+	state, err := resourceDatabricksCluster().Refresh(
+		&terraform.InstanceState{
+			ID:         info.ClusterId,
+			Attributes: attributes,
+		},
+		testDatabricksClusterClient(cl),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if state.Attributes["data_security_mode"] != "USER_ISOLATION" ||
+		state.Attributes["single_user_name"] != "" ||
+		state.Attributes["runtime_engine"] != "PHOTON" {
+		t.Fatalf("Wrong state: %v", state.Attributes)
+	}
+}
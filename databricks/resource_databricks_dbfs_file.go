@@ -0,0 +1,231 @@
+package databricks
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/hashicorp/terraform/helper/schema"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+func resourceDatabricksDbfsFile() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatabricksDbfsFileCreate,
+		Read:   resourceDatabricksDbfsFileRead,
+		Update: resourceDatabricksDbfsFileUpdate,
+		Delete: resourceDatabricksDbfsFileDelete,
+
+		CustomizeDiff: resourceDatabricksDbfsFileCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"content_base64"},
+			},
+			"content_base64": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"source"},
+				StateFunc: func(v interface{}) string {
+					// Only a hash of the content is kept in the state.
+					hash, err := resourceDatabricksDbfsFileMd5(v.(string), "")
+					if err != nil {
+						return ""
+					}
+					return hash
+				},
+			},
+			"overwrite": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"md5": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"file_size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDatabricksDbfsFileCreate(d *schema.ResourceData, m interface{}) error {
+	log.Print("[DEBUG] Creating DBFS file")
+
+	path := d.Get("path").(string)
+
+	err := resourceDatabricksDbfsFileUpload(d, m)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(path)
+
+	log.Printf("[DEBUG] DBFS file ID: %s", d.Id())
+
+	return resourceDatabricksDbfsFileRead(d, m)
+}
+
+func resourceDatabricksDbfsFileRead(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).dbfs
+
+	resp, err := apiClient.GetStatus(&dbfsGetStatusRequest{
+		Path: d.Id(),
+	})
+	if err != nil {
+		if resourceDatabricksDbfsFileNotExistsError(err) {
+			log.Printf("[WARN] DBFS file (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// Reading back the whole file on every refresh would be too expensive,
+	// so changes are detected through the size of the file: if it does not
+	// match the one of the uploaded content, the hash is cleared so that the
+	// file gets uploaded again.
+	if size, ok := d.GetOk("file_size"); ok && int64(size.(int)) != resp.FileSize {
+		log.Printf("[WARN] DBFS file (%s) was modified outside of Terraform", d.Id())
+		d.Set("md5", "")
+	}
+
+	d.Set("path", resp.Path)
+	d.Set("file_size", resp.FileSize)
+
+	return nil
+}
+
+func resourceDatabricksDbfsFileUpdate(d *schema.ResourceData, m interface{}) error {
+	// Changes to the content force a new file (see the diff customization),
+	// so only attributes that do not affect the file itself reach this point.
+	return resourceDatabricksDbfsFileRead(d, m)
+}
+
+func resourceDatabricksDbfsFileDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).dbfs
+
+	log.Printf("[DEBUG] Deleting DBFS file: %s", d.Id())
+
+	err := apiClient.Delete(&dbfsDeleteRequest{
+		Path: d.Id(),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksDbfsFileCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("source") || !d.NewValueKnown("content_base64") {
+		if err := d.SetNewComputed("md5"); err != nil {
+			return err
+		}
+		return d.ForceNew("md5")
+	}
+
+	var hash string
+	var err error
+
+	if source := d.Get("source").(string); source != "" {
+		hash, err = resourceDatabricksDbfsFileMd5("", source)
+	} else if d.HasChange("content_base64") {
+		hash, err = resourceDatabricksDbfsFileMd5(d.Get("content_base64").(string), "")
+	} else {
+		// Unchanged inline content is only known through its hash, which is
+		// what the state holds.
+		hash = d.Get("content_base64").(string)
+	}
+	if err != nil {
+		return err
+	}
+
+	if hash != d.Get("md5").(string) {
+		if err := d.SetNew("md5", hash); err != nil {
+			return err
+		}
+		return d.ForceNew("md5")
+	}
+
+	return nil
+}
+
+func resourceDatabricksDbfsFileUpload(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).dbfs
+
+	reader, err := resourceDatabricksDbfsFileOpen(
+		d.Get("content_base64").(string),
+		d.Get("source").(string),
+	)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	hash := md5.New()
+
+	err = apiClient.Upload(d.Get("path").(string), io.TeeReader(reader, hash), d.Get("overwrite").(bool))
+	if err != nil {
+		return err
+	}
+
+	d.Set("md5", hex.EncodeToString(hash.Sum(nil)))
+
+	return nil
+}
+
+// resourceDatabricksDbfsFileOpen returns a reader for the content of the
+// file, which is either given inline (base64-encoded) or as a local path.
+func resourceDatabricksDbfsFileOpen(contentBase64 string, source string) (io.ReadCloser, error) {
+	if source != "" {
+		return os.Open(source)
+	}
+
+	if contentBase64 != "" {
+		content, err := base64.StdEncoding.DecodeString(contentBase64)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(content)), nil
+	}
+
+	return nil, errors.New("either source or content_base64 must be set")
+}
+
+func resourceDatabricksDbfsFileMd5(contentBase64 string, source string) (string, error) {
+	reader, err := resourceDatabricksDbfsFileOpen(contentBase64, source)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func resourceDatabricksDbfsFileNotExistsError(err error) bool {
+	databricksError, ok := err.(client.Error)
+	return ok && databricksError.Code() == "RESOURCE_DOES_NOT_EXIST"
+}
@@ -0,0 +1,193 @@
+package databricks
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAccDatabricksDbfsFile_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksDbfsFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksDbfsFileConfig("echo hello"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksDbfsFileExists("databricks_dbfs_file.file"),
+					resource.TestCheckResourceAttr(
+						"databricks_dbfs_file.file", "file_size", "11"),
+					resource.TestCheckResourceAttr(
+						"databricks_dbfs_file.file", "md5", "664d0430ee33458602e580520841a2d4"),
+				),
+			},
+			{
+				Config: testAccDatabricksDbfsFileConfig("echo goodbye"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksDbfsFileExists("databricks_dbfs_file.file"),
+					resource.TestCheckResourceAttr(
+						"databricks_dbfs_file.file", "file_size", "13"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksDbfsFileExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*Client).dbfs
+
+		_, err := conn.GetStatus(&dbfsGetStatusRequest{
+			Path: rs.Primary.ID,
+		})
+
+		return err
+	}
+}
+
+func testAccCheckDatabricksDbfsFileDestroy(s *terraform.State) error {
+	endpoint := testAccProvider.Meta().(*Client).dbfs
+
+	path := s.RootModule().Resources["databricks_dbfs_file.file"].Primary.ID
+
+	_, err := endpoint.GetStatus(&dbfsGetStatusRequest{
+		Path: path,
+	})
+
+	if err == nil {
+		return errors.New("DBFS file still exists")
+	}
+
+	if !resourceDatabricksDbfsFileNotExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func testAccDatabricksDbfsFileConfig(content string) string {
+	const formatStr = `
+resource "databricks_dbfs_file" "file" {
+	path           = "/tmp/tf-test/init.sh"
+	content_base64 = "${base64encode("%s\n")}"
+	overwrite      = true
+}
+`
+	return fmt.Sprintf(formatStr, content)
+}
+
+func TestDatabricksDbfsFile_md5MatchesForSourceAndContent(t *testing.T) {
+	content := []byte("print('generated by terraform')\n")
+
+	f, err := ioutil.TempFile("", "tf-test-dbfs-file")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(content); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	f.Close()
+
+	fromSource, err := resourceDatabricksDbfsFileMd5("", f.Name())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	fromContent, err := resourceDatabricksDbfsFileMd5(base64.StdEncoding.EncodeToString(content), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if fromSource != fromContent {
+		t.Fatalf("Hashes differ: %s != %s", fromSource, fromContent)
+	}
+
+	if fromSource != "f7dcac98561fe56b0eb85209b0ff384d" {
+		t.Fatalf("Wrong hash: %s", fromSource)
+	}
+}
+
+func TestDatabricksDbfsFile_openFailsWithoutContent(t *testing.T) {
+	if _, err := resourceDatabricksDbfsFileOpen("", ""); err == nil {
+		t.Fatal("No error was returned when no content was given")
+	}
+}
+
+func TestDatabricksDbfsFile_openFailsWithInvalidBase64(t *testing.T) {
+	if _, err := resourceDatabricksDbfsFileOpen("not base64!", ""); err == nil {
+		t.Fatal("No error was returned when invalid content was given")
+	}
+}
+
+func TestDatabricksDbfsFile_uploadCleansUpAfterFailure(t *testing.T) {
+	cases := []struct {
+		reader   io.Reader
+		addBlock interface{}
+	}{
+		{
+			reader: strings.NewReader("content"),
+			addBlock: testDatabricksErrorResponse{
+				StatusCode: 400,
+				ErrorCode:  "MAX_BLOCK_SIZE_EXCEEDED",
+				Message:    "Block too large",
+			},
+		},
+		{
+			reader: io.MultiReader(strings.NewReader("content"), &testDatabricksFailingReader{}),
+		},
+	}
+
+	for _, c := range cases {
+		var calls []string
+
+		handler := func(path string, body []byte) interface{} {
+			calls = append(calls, path)
+			switch path {
+			case "dbfs/create":
+				return dbfsCreateResponse{Handle: 1}
+			case "dbfs/add-block":
+				return c.addBlock
+			}
+			return nil
+		}
+
+		cl, closeServer := testDatabricksServer(t, handler)
+
+		err := (&dbfsEndpoint{Client: cl}).Upload("/tmp/tf-test", c.reader, false)
+		closeServer()
+
+		if err == nil {
+			t.Fatal("No error was returned for a failed upload")
+		}
+
+		if calls[len(calls)-2] != "dbfs/close" || calls[len(calls)-1] != "dbfs/delete" {
+			t.Fatalf("Partial file was not cleaned up: %v", calls)
+		}
+	}
+}
+
+// testDatabricksFailingReader fails as a local file that cannot be read.
+type testDatabricksFailingReader struct{}
+
+func (r *testDatabricksFailingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
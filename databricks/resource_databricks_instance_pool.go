@@ -0,0 +1,382 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strings"
+)
+
+func resourceDatabricksInstancePool() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatabricksInstancePoolCreate,
+		Read:   resourceDatabricksInstancePoolRead,
+		Update: resourceDatabricksInstancePoolUpdate,
+		Delete: resourceDatabricksInstancePoolDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"min_idle_instances": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"max_capacity": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"idle_instance_autotermination_minutes": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"node_type_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"custom_tags": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"enable_elastic_disk": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"preloaded_spark_versions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"disk_spec": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ebs_volume_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							ValidateFunc: validateStringInSlice([]string{
+								"GENERAL_PURPOSE_SSD",
+								"THROUGHPUT_OPTIMIZED_HDD",
+							}),
+						},
+						"azure_disk_volume_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							ValidateFunc: validateStringInSlice([]string{
+								"PREMIUM_LRS",
+								"STANDARD_LRS",
+							}),
+						},
+						"disk_count": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							ForceNew: true,
+						},
+						"disk_size": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"aws_attributes": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"azure_attributes"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"availability": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+							ValidateFunc: validateStringInSlice([]string{
+								"SPOT",
+								"ON_DEMAND",
+							}),
+						},
+						"zone_id": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+						"spot_bid_price_percent": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"azure_attributes": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"aws_attributes"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"availability": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+							ValidateFunc: validateStringInSlice([]string{
+								"SPOT_AZURE",
+								"ON_DEMAND_AZURE",
+							}),
+						},
+						"spot_bid_max_price": {
+							Type:     schema.TypeFloat,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+					},
+				},
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"default_tags": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceDatabricksInstancePoolCreate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).instancePools
+
+	log.Print("[DEBUG] Creating instance pool")
+
+	resp, err := apiClient.Create(resourceDatabricksInstancePoolExpand(d))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resp.InstancePoolId)
+
+	log.Printf("[DEBUG] Instance pool ID: %s", d.Id())
+
+	return resourceDatabricksInstancePoolRead(d, m)
+}
+
+func resourceDatabricksInstancePoolRead(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).instancePools
+
+	resp, err := apiClient.Get(&instancePoolsIdRequest{
+		InstancePoolId: d.Id(),
+	})
+	if err != nil {
+		if resourceDatabricksInstancePoolNotExistsError(err) {
+			log.Printf("[WARN] Instance pool (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// Deleted pools can still be read for a while.
+	if resp.State == "DELETED" {
+		log.Printf("[WARN] Instance pool (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("name", resp.InstancePoolName)
+	d.Set("min_idle_instances", resp.MinIdleInstances)
+	d.Set("max_capacity", resp.MaxCapacity)
+	d.Set("idle_instance_autotermination_minutes", resp.IdleInstanceAutoterminationMinutes)
+	d.Set("node_type_id", resp.NodeTypeId)
+	d.Set("custom_tags", resp.CustomTags)
+	d.Set("enable_elastic_disk", resp.EnableElasticDisk)
+	d.Set("preloaded_spark_versions", resp.PreloadedSparkVersions)
+	d.Set("disk_spec", resourceDatabricksInstancePoolFlattenDiskSpec(resp.DiskSpec))
+	d.Set("aws_attributes", resourceDatabricksInstancePoolFlattenAwsAttributes(resp.AwsAttributes))
+	d.Set("azure_attributes", resourceDatabricksInstancePoolFlattenAzureAttributes(resp.AzureAttributes))
+	d.Set("state", resp.State)
+	d.Set("default_tags", resp.DefaultTags)
+
+	return nil
+}
+
+func resourceDatabricksInstancePoolUpdate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).instancePools
+
+	log.Printf("[DEBUG] Updating instance pool: %s", d.Id())
+
+	spec := resourceDatabricksInstancePoolExpand(d)
+
+	err := apiClient.Edit(&instancePoolsEditRequest{
+		InstancePoolId:                     d.Id(),
+		InstancePoolName:                   spec.InstancePoolName,
+		MinIdleInstances:                   spec.MinIdleInstances,
+		MaxCapacity:                        spec.MaxCapacity,
+		NodeTypeId:                         spec.NodeTypeId,
+		IdleInstanceAutoterminationMinutes: spec.IdleInstanceAutoterminationMinutes,
+		CustomTags:                         spec.CustomTags,
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceDatabricksInstancePoolRead(d, m)
+}
+
+func resourceDatabricksInstancePoolDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).instancePools
+
+	log.Printf("[DEBUG] Deleting instance pool: %s", d.Id())
+
+	err := apiClient.Delete(&instancePoolsIdRequest{
+		InstancePoolId: d.Id(),
+	})
+	if err != nil && !resourceDatabricksInstancePoolNotExistsError(err) {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksInstancePoolExpand(d *schema.ResourceData) *instancePoolSpec {
+	return &instancePoolSpec{
+		InstancePoolName:                   d.Get("name").(string),
+		MinIdleInstances:                   int32(d.Get("min_idle_instances").(int)),
+		MaxCapacity:                        int32(d.Get("max_capacity").(int)),
+		NodeTypeId:                         d.Get("node_type_id").(string),
+		IdleInstanceAutoterminationMinutes: int32(d.Get("idle_instance_autotermination_minutes").(int)),
+		CustomTags:                         expandStringMap(d.Get("custom_tags").(map[string]interface{})),
+		EnableElasticDisk:                  d.Get("enable_elastic_disk").(bool),
+		PreloadedSparkVersions:             expandStringList(d.Get("preloaded_spark_versions").([]interface{})),
+		DiskSpec:                           resourceDatabricksInstancePoolExpandDiskSpec(d.Get("disk_spec").([]interface{})),
+		AwsAttributes:                      resourceDatabricksInstancePoolExpandAwsAttributes(d.Get("aws_attributes").([]interface{})),
+		AzureAttributes:                    resourceDatabricksInstancePoolExpandAzureAttributes(d.Get("azure_attributes").([]interface{})),
+	}
+}
+
+func resourceDatabricksInstancePoolExpandDiskSpec(diskSpec []interface{}) *instancePoolDiskSpec {
+	if len(diskSpec) == 0 || diskSpec[0] == nil {
+		return nil
+	}
+
+	diskSpecElem := diskSpec[0].(map[string]interface{})
+
+	result := &instancePoolDiskSpec{
+		DiskCount: int32(diskSpecElem["disk_count"].(int)),
+		DiskSize:  int32(diskSpecElem["disk_size"].(int)),
+	}
+
+	diskType := instancePoolDiskType{
+		EbsVolumeType:       diskSpecElem["ebs_volume_type"].(string),
+		AzureDiskVolumeType: diskSpecElem["azure_disk_volume_type"].(string),
+	}
+	if diskType.EbsVolumeType != "" || diskType.AzureDiskVolumeType != "" {
+		result.DiskType = &diskType
+	}
+
+	return result
+}
+
+func resourceDatabricksInstancePoolFlattenDiskSpec(diskSpec *instancePoolDiskSpec) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if diskSpec != nil {
+		diskSpecElem := map[string]interface{}{
+			"disk_count": int(diskSpec.DiskCount),
+			"disk_size":  int(diskSpec.DiskSize),
+		}
+
+		if diskSpec.DiskType != nil {
+			diskSpecElem["ebs_volume_type"] = diskSpec.DiskType.EbsVolumeType
+			diskSpecElem["azure_disk_volume_type"] = diskSpec.DiskType.AzureDiskVolumeType
+		}
+
+		result = append(result, diskSpecElem)
+	}
+	return result
+}
+
+func resourceDatabricksInstancePoolExpandAwsAttributes(awsAttributes []interface{}) *instancePoolAwsAttributes {
+	if len(awsAttributes) == 0 || awsAttributes[0] == nil {
+		return nil
+	}
+
+	awsAttributesElem := awsAttributes[0].(map[string]interface{})
+
+	return &instancePoolAwsAttributes{
+		Availability:        awsAttributesElem["availability"].(string),
+		ZoneId:              awsAttributesElem["zone_id"].(string),
+		SpotBidPricePercent: int32(awsAttributesElem["spot_bid_price_percent"].(int)),
+	}
+}
+
+func resourceDatabricksInstancePoolFlattenAwsAttributes(awsAttributes *instancePoolAwsAttributes) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if awsAttributes != nil {
+		result = append(result, map[string]interface{}{
+			"availability":           awsAttributes.Availability,
+			"zone_id":                awsAttributes.ZoneId,
+			"spot_bid_price_percent": int(awsAttributes.SpotBidPricePercent),
+		})
+	}
+	return result
+}
+
+func resourceDatabricksInstancePoolExpandAzureAttributes(azureAttributes []interface{}) *instancePoolAzureAttributes {
+	if len(azureAttributes) == 0 || azureAttributes[0] == nil {
+		return nil
+	}
+
+	azureAttributesElem := azureAttributes[0].(map[string]interface{})
+
+	return &instancePoolAzureAttributes{
+		Availability:    azureAttributesElem["availability"].(string),
+		SpotBidMaxPrice: azureAttributesElem["spot_bid_max_price"].(float64),
+	}
+}
+
+func resourceDatabricksInstancePoolFlattenAzureAttributes(azureAttributes *instancePoolAzureAttributes) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if azureAttributes != nil {
+		result = append(result, map[string]interface{}{
+			"availability":       azureAttributes.Availability,
+			"spot_bid_max_price": azureAttributes.SpotBidMaxPrice,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksInstancePoolNotExistsError(err error) bool {
+	databricksError, ok := err.(client.Error)
+	return ok &&
+		(databricksError.Code() == "RESOURCE_DOES_NOT_EXIST" ||
+			databricksError.Code() == "INVALID_PARAMETER_VALUE" &&
+				strings.Contains(databricksError.Error(), "does not exist"))
+}
@@ -0,0 +1,194 @@
+package databricks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"reflect"
+	"testing"
+)
+
+func TestAccDatabricksInstancePool_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksInstancePoolDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksInstancePoolConfig(0),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksInstancePoolExists("databricks_instance_pool.pool"),
+					resource.TestCheckResourceAttr(
+						"databricks_instance_pool.pool", "name", "tf-test-pool"),
+					resource.TestCheckResourceAttr(
+						"databricks_instance_pool.pool", "min_idle_instances", "0"),
+					resource.TestCheckResourceAttr(
+						"databricks_cluster.cluster", "node_type_id", "Standard_D3_v2"),
+				),
+			},
+			{
+				Config: testAccDatabricksInstancePoolConfig(1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksInstancePoolExists("databricks_instance_pool.pool"),
+					resource.TestCheckResourceAttr(
+						"databricks_instance_pool.pool", "min_idle_instances", "1"),
+				),
+			},
+			{
+				ResourceName:      "databricks_instance_pool.pool",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksInstancePoolExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		conn := testAccProvider.Meta().(*Client).instancePools
+
+		_, err := conn.Get(&instancePoolsIdRequest{
+			InstancePoolId: rs.Primary.ID,
+		})
+
+		return err
+	}
+}
+
+func testAccCheckDatabricksInstancePoolDestroy(s *terraform.State) error {
+	endpoint := testAccProvider.Meta().(*Client).instancePools
+
+	resp, err := endpoint.Get(&instancePoolsIdRequest{
+		InstancePoolId: s.RootModule().Resources["databricks_instance_pool.pool"].Primary.ID,
+	})
+	if err != nil {
+		if resourceDatabricksInstancePoolNotExistsError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if resp.State != "DELETED" {
+		return errors.New("instance pool still exists")
+	}
+
+	return nil
+}
+
+func testAccDatabricksInstancePoolConfig(minIdleInstances int) string {
+	return fmt.Sprintf(`
+resource "databricks_instance_pool" "pool" {
+	name                                  = "tf-test-pool"
+	node_type_id                          = "Standard_D3_v2"
+	min_idle_instances                    = %d
+	max_capacity                          = 4
+	idle_instance_autotermination_minutes = 10
+	preloaded_spark_versions              = ["4.2.x-scala2.11"]
+
+	disk_spec {
+		azure_disk_volume_type = "STANDARD_LRS"
+		disk_count             = 1
+		disk_size              = 64
+	}
+}
+
+resource "databricks_cluster" "cluster" {
+	name                    = "tf-test-pool-cluster"
+	spark_version           = "4.2.x-scala2.11"
+	instance_pool_id        = "${databricks_instance_pool.pool.id}"
+	num_workers             = 1
+	autotermination_minutes = 10
+	permanently_delete      = true
+}
+`, minIdleInstances)
+}
+
+func TestDatabricksInstancePool_expandsDiskSpec(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksInstancePool().Schema, map[string]interface{}{
+		"name":                                  "tf-test-pool",
+		"node_type_id":                          "m4.large",
+		"idle_instance_autotermination_minutes": 10,
+		"disk_spec": []interface{}{
+			map[string]interface{}{
+				"ebs_volume_type": "GENERAL_PURPOSE_SSD",
+				"disk_count":      2,
+				"disk_size":       100,
+			},
+		},
+		"aws_attributes": []interface{}{
+			map[string]interface{}{
+				"availability": "SPOT",
+				"zone_id":      "us-west-2a",
+			},
+		},
+	})
+
+	spec := resourceDatabricksInstancePoolExpand(d)
+
+	expected := &instancePoolDiskSpec{
+		DiskType:  &instancePoolDiskType{EbsVolumeType: "GENERAL_PURPOSE_SSD"},
+		DiskCount: 2,
+		DiskSize:  100,
+	}
+	if !reflect.DeepEqual(spec.DiskSpec, expected) {
+		t.Fatalf("Wrong disk spec: %+v", spec.DiskSpec)
+	}
+
+	if spec.AwsAttributes == nil || spec.AwsAttributes.Availability != "SPOT" || spec.AzureAttributes != nil {
+		t.Fatalf("Wrong attributes: %+v, %+v", spec.AwsAttributes, spec.AzureAttributes)
+	}
+}
+
+func TestDatabricksInstancePool_updateOnlySendsEditableAttributes(t *testing.T) {
+	var edit map[string]interface{}
+
+	handler := func(path string, body []byte) interface{} {
+		switch path {
+		case "instance-pools/edit":
+			json.Unmarshal(body, &edit)
+		case "instance-pools/get":
+			return instancePoolInfo{InstancePoolId: "0123-456789-pool1", State: "ACTIVE"}
+		}
+		return nil
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	d := schema.TestResourceDataRaw(t, resourceDatabricksInstancePool().Schema, map[string]interface{}{
+		"name":                                  "tf-test-pool",
+		"node_type_id":                          "m4.large",
+		"max_capacity":                          4,
+		"idle_instance_autotermination_minutes": 10,
+		"preloaded_spark_versions":              []interface{}{"4.2.x-scala2.11"},
+	})
+	d.SetId("0123-456789-pool1")
+
+	err := resourceDatabricksInstancePoolUpdate(d, &Client{instancePools: &instancePoolsEndpoint{Client: cl}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := map[string]interface{}{
+		"instance_pool_id":                      "0123-456789-pool1",
+		"instance_pool_name":                    "tf-test-pool",
+		"max_capacity":                          float64(4),
+		"node_type_id":                          "m4.large",
+		"idle_instance_autotermination_minutes": float64(10),
+	}
+	if !reflect.DeepEqual(edit, expected) {
+		t.Fatalf("Wrong edit request: %v", edit)
+	}
+}
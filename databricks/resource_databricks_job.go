@@ -0,0 +1,1011 @@
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var jobTaskTypes = []string{
+	"notebook_task",
+	"spark_jar_task",
+	"spark_python_task",
+	"spark_submit_task",
+}
+
+func resourceDatabricksJob() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatabricksJobCreate,
+		Read:   resourceDatabricksJobRead,
+		Update: resourceDatabricksJobUpdate,
+		Delete: resourceDatabricksJobDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceDatabricksJobCustomizeDiff,
+
+		// The cluster specs of jobs follow the ones of clusters.
+		SchemaVersion: 1,
+		MigrateState:  resourceDatabricksClusterMigrateState,
+
+		Schema: resourceDatabricksJobSchema(),
+	}
+}
+
+func resourceDatabricksJobSchema() map[string]*schema.Schema {
+	s := resourceDatabricksJobTaskSettingsSchema()
+
+	s["existing_cluster_id"].ConflictsWith = []string{"new_cluster", "task"}
+	s["new_cluster"].ConflictsWith = []string{"existing_cluster_id", "task"}
+	for _, taskType := range jobTaskTypes {
+		for _, other := range jobTaskTypes {
+			if other != taskType {
+				s[taskType].ConflictsWith = append(s[taskType].ConflictsWith, other)
+			}
+		}
+		s[taskType].ConflictsWith = append(s[taskType].ConflictsWith, "task")
+	}
+
+	// Retries are only set per task in multi-task jobs, whereas the timeout
+	// and the email notifications can also be set for the whole job.
+	s["max_retries"].ConflictsWith = []string{"task"}
+	s["min_retry_interval_millis"].ConflictsWith = []string{"task"}
+	s["retry_on_timeout"].ConflictsWith = []string{"task"}
+
+	s["name"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["schedule"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"quartz_cron_expression": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"timezone_id": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"pause_status": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Computed:     true,
+					ValidateFunc: validateStringInSlice([]string{"PAUSED", "UNPAUSED"}),
+				},
+			},
+		},
+	}
+	s["max_concurrent_runs"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Optional: true,
+		Default:  1,
+	}
+	s["task"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: resourceDatabricksJobTaskSchema(),
+		},
+	}
+	s["job_cluster"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"job_cluster_key": {
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"new_cluster": {
+					Type:     schema.TypeList,
+					Required: true,
+					MaxItems: 1,
+					Elem: &schema.Resource{
+						Schema: resourceDatabricksClusterSpecSchema(),
+					},
+				},
+			},
+		},
+	}
+
+	return s
+}
+
+func resourceDatabricksJobTaskSchema() map[string]*schema.Schema {
+	s := resourceDatabricksJobTaskSettingsSchema()
+
+	s["task_key"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["description"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+	s["depends_on"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	s["job_cluster_key"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	}
+
+	return s
+}
+
+// resourceDatabricksJobTaskSettingsSchema returns the schema of the
+// attributes that single-task jobs define at the top level, and multi-task
+// jobs define on each task block.
+func resourceDatabricksJobTaskSettingsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"existing_cluster_id": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"new_cluster": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: resourceDatabricksClusterSpecSchema(),
+			},
+		},
+		"notebook_task": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"notebook_path": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"base_parameters": {
+						Type:     schema.TypeMap,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"spark_jar_task": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"jar_uri": {
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"main_class_name": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"parameters": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"spark_python_task": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"python_file": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"parameters": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"spark_submit_task": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"parameters": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+				},
+			},
+		},
+		"email_notifications": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"on_start": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"on_success": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"on_failure": {
+						Type:     schema.TypeList,
+						Optional: true,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"no_alert_for_skipped_runs": {
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+				},
+			},
+		},
+		"max_retries": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"min_retry_interval_millis": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"retry_on_timeout": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+		"timeout_seconds": {
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+	}
+}
+
+func resourceDatabricksJobCreate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).jobs
+
+	log.Print("[DEBUG] Creating job")
+
+	settings, err := resourceDatabricksJobExpandSettings(d)
+	if err != nil {
+		return err
+	}
+
+	resp, err := apiClient.Create(settings)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.FormatInt(resp.JobId, 10))
+
+	log.Printf("[DEBUG] Job ID: %s", d.Id())
+
+	return resourceDatabricksJobRead(d, m)
+}
+
+func resourceDatabricksJobRead(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).jobs
+
+	jobId, err := resourceDatabricksJobParseId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	request := jobsGetRequest{
+		JobId:     jobId,
+		MultiTask: len(d.Get("task").([]interface{})) > 0,
+	}
+
+	resp, err := apiClient.Get(&request)
+	if err == nil && !request.MultiTask && resp.Settings != nil && resp.Settings.isMultiTask() {
+		// The job is not known to have tasks (e.g., it is being imported),
+		// so it must be read again for its tasks to be returned.
+		request.MultiTask = true
+		resp, err = apiClient.Get(&request)
+	}
+	if err != nil {
+		if resourceDatabricksJobNotExistsError(err) {
+			log.Printf("[WARN] Job (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	settings := resp.Settings
+	if settings == nil {
+		settings = &jobSettings{}
+	}
+
+	resourceDatabricksJobKeepDockerPasswords(settings, d)
+
+	d.Set("name", settings.Name)
+	for k, v := range resourceDatabricksJobFlattenTaskSettings(&settings.jobTaskSettings) {
+		d.Set(k, v)
+	}
+	d.Set("schedule", resourceDatabricksJobFlattenSchedule(settings.Schedule))
+	d.Set("max_concurrent_runs", settings.MaxConcurrentRuns)
+
+	tasks := resourceDatabricksJobSortTasks(settings.Tasks, d.Get("task").([]interface{}))
+	d.Set("task", resourceDatabricksJobFlattenTasks(tasks))
+
+	jobClusters := resourceDatabricksJobSortJobClusters(settings.JobClusters, d.Get("job_cluster").([]interface{}))
+	d.Set("job_cluster", resourceDatabricksJobFlattenJobClusters(jobClusters))
+
+	return nil
+}
+
+// resourceDatabricksJobKeepDockerPasswords keeps the docker passwords of the
+// new_cluster blocks in the state, as the API does not return them. The
+// blocks of tasks and job clusters are matched by their keys.
+func resourceDatabricksJobKeepDockerPasswords(settings *jobSettings, d *schema.ResourceData) {
+	resourceDatabricksClusterKeepDockerPassword(settings.NewCluster, d, "new_cluster.0.")
+
+	taskIndexes := make(map[string]int)
+	for i, v := range d.Get("task").([]interface{}) {
+		taskIndexes[v.(map[string]interface{})["task_key"].(string)] = i
+	}
+
+	for _, task := range settings.Tasks {
+		if i, ok := taskIndexes[task.TaskKey]; ok {
+			resourceDatabricksClusterKeepDockerPassword(task.NewCluster, d, fmt.Sprintf("task.%d.new_cluster.0.", i))
+		}
+	}
+
+	jobClusterIndexes := make(map[string]int)
+	for i, v := range d.Get("job_cluster").([]interface{}) {
+		jobClusterIndexes[v.(map[string]interface{})["job_cluster_key"].(string)] = i
+	}
+
+	for _, jobCluster := range settings.JobClusters {
+		if i, ok := jobClusterIndexes[jobCluster.JobClusterKey]; ok {
+			resourceDatabricksClusterKeepDockerPassword(
+				jobCluster.NewCluster, d, fmt.Sprintf("job_cluster.%d.new_cluster.0.", i))
+		}
+	}
+}
+
+func resourceDatabricksJobUpdate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).jobs
+
+	log.Printf("[DEBUG] Updating job: %s", d.Id())
+
+	jobId, err := resourceDatabricksJobParseId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	settings, err := resourceDatabricksJobExpandSettings(d)
+	if err != nil {
+		return err
+	}
+
+	err = apiClient.Reset(&jobsResetRequest{
+		JobId:       jobId,
+		NewSettings: settings,
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceDatabricksJobRead(d, m)
+}
+
+func resourceDatabricksJobDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).jobs
+
+	log.Printf("[DEBUG] Deleting job: %s", d.Id())
+
+	jobId, err := resourceDatabricksJobParseId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = apiClient.Delete(&jobsDeleteRequest{
+		JobId: jobId,
+	})
+	if err != nil && !resourceDatabricksJobNotExistsError(err) {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksJobParseId(id string) (int64, error) {
+	jobId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid job ID (%s): %s", id, err)
+	}
+	return jobId, nil
+}
+
+func resourceDatabricksJobNotExistsError(err error) bool {
+	databricksError, ok := err.(client.Error)
+	if !ok {
+		return false
+	}
+
+	switch databricksError.Code() {
+	case "RESOURCE_DOES_NOT_EXIST":
+		return true
+	case "INVALID_PARAMETER_VALUE":
+		return strings.Contains(databricksError.Error(), "does not exist")
+	default:
+		return false
+	}
+}
+
+func resourceDatabricksJobCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	// Jobs with tasks cannot be turned back into single-task jobs, as the 2.0
+	// API keeps their tasks, so they are replaced instead.
+	oldTasks, newTasks := d.GetChange("task")
+	if len(oldTasks.([]interface{})) > 0 && len(newTasks.([]interface{})) == 0 {
+		if err := d.ForceNew("task"); err != nil {
+			return err
+		}
+	}
+
+	for _, prefix := range resourceDatabricksJobClusterSpecPrefixes(d) {
+		if err := resourceDatabricksClusterValidateSpec(d, prefix); err != nil {
+			return err
+		}
+	}
+
+	return resourceDatabricksJobValidateTasks(
+		d.Get("task").([]interface{}),
+		d.Get("job_cluster").([]interface{}),
+	)
+}
+
+// resourceDatabricksJobClusterSpecPrefixes returns the prefixes of the keys of
+// all the new_cluster blocks of the job (i.e., the one of the job, and the
+// ones of its tasks and job clusters).
+func resourceDatabricksJobClusterSpecPrefixes(d *schema.ResourceDiff) []string {
+	var prefixes []string
+
+	if len(d.Get("new_cluster").([]interface{})) > 0 {
+		prefixes = append(prefixes, "new_cluster.0.")
+	}
+
+	for i, v := range d.Get("task").([]interface{}) {
+		if len(v.(map[string]interface{})["new_cluster"].([]interface{})) > 0 {
+			prefixes = append(prefixes, fmt.Sprintf("task.%d.new_cluster.0.", i))
+		}
+	}
+
+	for i := range d.Get("job_cluster").([]interface{}) {
+		prefixes = append(prefixes, fmt.Sprintf("job_cluster.%d.new_cluster.0.", i))
+	}
+
+	return prefixes
+}
+
+// resourceDatabricksJobValidateTasks checks that task keys are unique, that
+// dependencies and job clusters refer to existing keys, and that there are
+// no dependency cycles.
+func resourceDatabricksJobValidateTasks(tasks []interface{}, jobClusters []interface{}) error {
+	jobClusterKeys := make(map[string]bool, len(jobClusters))
+	for _, v := range jobClusters {
+		key := v.(map[string]interface{})["job_cluster_key"].(string)
+		if jobClusterKeys[key] {
+			return fmt.Errorf("duplicate job_cluster_key: %s", key)
+		}
+		jobClusterKeys[key] = true
+	}
+
+	keys := make([]string, 0, len(tasks))
+	dependencies := make(map[string][]string, len(tasks))
+	for _, v := range tasks {
+		task := v.(map[string]interface{})
+
+		key := task["task_key"].(string)
+		if _, ok := dependencies[key]; ok {
+			return fmt.Errorf("duplicate task_key: %s", key)
+		}
+
+		if jobClusterKey := task["job_cluster_key"].(string); jobClusterKey != "" && !jobClusterKeys[jobClusterKey] {
+			return fmt.Errorf("task %s refers to an unknown job cluster: %s", key, jobClusterKey)
+		}
+
+		keys = append(keys, key)
+		dependencies[key] = expandStringList(task["depends_on"].([]interface{}))
+	}
+
+	for _, key := range keys {
+		for _, dependency := range dependencies[key] {
+			if _, ok := dependencies[dependency]; !ok {
+				return fmt.Errorf("task %s depends on an unknown task: %s", key, dependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(keys))
+	path := make([]string, 0, len(keys))
+
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visiting:
+			return fmt.Errorf("task dependencies contain a cycle: %s -> %s", strings.Join(path, " -> "), key)
+		case visited:
+			return nil
+		}
+
+		state[key] = visiting
+		path = append(path, key)
+
+		for _, dependency := range dependencies[key] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[key] = visited
+
+		return nil
+	}
+
+	for _, key := range keys {
+		if state[key] == unvisited {
+			if err := visit(key); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func resourceDatabricksJobExpandSettings(d *schema.ResourceData) (*jobSettings, error) {
+	settings := jobSettings{
+		Name:              d.Get("name").(string),
+		MaxConcurrentRuns: int32(d.Get("max_concurrent_runs").(int)),
+	}
+
+	if v, ok := d.GetOk("schedule"); ok {
+		settings.Schedule = resourceDatabricksJobExpandSchedule(v.([]interface{}))
+	}
+
+	if v, ok := d.GetOk("job_cluster"); ok {
+		settings.JobClusters = resourceDatabricksJobExpandJobClusters(v.([]interface{}))
+	}
+
+	if v, ok := d.GetOk("task"); ok {
+		tasks, err := resourceDatabricksJobExpandTasks(v.([]interface{}))
+		if err != nil {
+			return nil, err
+		}
+
+		settings.Tasks = tasks
+		settings.Format = jobFormatMultiTask
+		settings.TimeoutSeconds = int32(d.Get("timeout_seconds").(int))
+
+		if v, ok := d.GetOk("email_notifications"); ok {
+			settings.EmailNotifications = resourceDatabricksJobExpandEmailNotifications(v.([]interface{}))
+		}
+
+		return &settings, nil
+	}
+
+	taskSettings := make(map[string]interface{})
+	for k := range resourceDatabricksJobTaskSettingsSchema() {
+		taskSettings[k] = d.Get(k)
+	}
+
+	settings.jobTaskSettings = resourceDatabricksJobExpandTaskSettings(taskSettings)
+
+	if err := resourceDatabricksJobCheckTaskSettings(&settings.jobTaskSettings, ""); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func resourceDatabricksJobExpandTaskSettings(taskSettings map[string]interface{}) jobTaskSettings {
+	result := jobTaskSettings{
+		ExistingClusterId:      taskSettings["existing_cluster_id"].(string),
+		MaxRetries:             int32(taskSettings["max_retries"].(int)),
+		MinRetryIntervalMillis: int32(taskSettings["min_retry_interval_millis"].(int)),
+		RetryOnTimeout:         taskSettings["retry_on_timeout"].(bool),
+		TimeoutSeconds:         int32(taskSettings["timeout_seconds"].(int)),
+	}
+
+	if v := taskSettings["new_cluster"].([]interface{}); len(v) > 0 {
+		spec := resourceDatabricksClusterExpandSpec(v[0].(map[string]interface{}))
+		result.NewCluster = &spec
+	}
+
+	if v := taskSettings["notebook_task"].([]interface{}); len(v) > 0 {
+		result.NotebookTask = resourceDatabricksJobExpandNotebookTask(v)
+	}
+
+	if v := taskSettings["spark_jar_task"].([]interface{}); len(v) > 0 {
+		result.SparkJarTask = resourceDatabricksJobExpandSparkJarTask(v)
+	}
+
+	if v := taskSettings["spark_python_task"].([]interface{}); len(v) > 0 {
+		result.SparkPythonTask = resourceDatabricksJobExpandSparkPythonTask(v)
+	}
+
+	if v := taskSettings["spark_submit_task"].([]interface{}); len(v) > 0 {
+		result.SparkSubmitTask = resourceDatabricksJobExpandSparkSubmitTask(v)
+	}
+
+	if v := taskSettings["email_notifications"].([]interface{}); len(v) > 0 {
+		result.EmailNotifications = resourceDatabricksJobExpandEmailNotifications(v)
+	}
+
+	return result
+}
+
+func resourceDatabricksJobFlattenTaskSettings(taskSettings *jobTaskSettings) map[string]interface{} {
+	return map[string]interface{}{
+		"existing_cluster_id":       taskSettings.ExistingClusterId,
+		"new_cluster":               resourceDatabricksJobFlattenNewCluster(taskSettings.NewCluster),
+		"notebook_task":             resourceDatabricksJobFlattenNotebookTask(taskSettings.NotebookTask),
+		"spark_jar_task":            resourceDatabricksJobFlattenSparkJarTask(taskSettings.SparkJarTask),
+		"spark_python_task":         resourceDatabricksJobFlattenSparkPythonTask(taskSettings.SparkPythonTask),
+		"spark_submit_task":         resourceDatabricksJobFlattenSparkSubmitTask(taskSettings.SparkSubmitTask),
+		"email_notifications":       resourceDatabricksJobFlattenEmailNotifications(taskSettings.EmailNotifications),
+		"max_retries":               int(taskSettings.MaxRetries),
+		"min_retry_interval_millis": int(taskSettings.MinRetryIntervalMillis),
+		"retry_on_timeout":          taskSettings.RetryOnTimeout,
+		"timeout_seconds":           int(taskSettings.TimeoutSeconds),
+	}
+}
+
+// resourceDatabricksJobCheckTaskSettings checks that the task runs on a
+// cluster and that exactly one task type is set.
+func resourceDatabricksJobCheckTaskSettings(taskSettings *jobTaskSettings, jobClusterKey string) error {
+	if taskSettings.ExistingClusterId == "" && taskSettings.NewCluster == nil && jobClusterKey == "" {
+		return errors.New("one of existing_cluster_id, new_cluster or job_cluster_key must be set")
+	}
+
+	taskCount := 0
+	if taskSettings.NotebookTask != nil {
+		taskCount++
+	}
+	if taskSettings.SparkJarTask != nil {
+		taskCount++
+	}
+	if taskSettings.SparkPythonTask != nil {
+		taskCount++
+	}
+	if taskSettings.SparkSubmitTask != nil {
+		taskCount++
+	}
+
+	if taskCount != 1 {
+		return fmt.Errorf("exactly one of %s must be set", strings.Join(jobTaskTypes, ", "))
+	}
+
+	return nil
+}
+
+func resourceDatabricksJobExpandTasks(tasks []interface{}) ([]jobTask, error) {
+	result := make([]jobTask, 0, len(tasks))
+
+	for _, v := range tasks {
+		taskElem := v.(map[string]interface{})
+
+		task := jobTask{
+			TaskKey:         taskElem["task_key"].(string),
+			Description:     taskElem["description"].(string),
+			JobClusterKey:   taskElem["job_cluster_key"].(string),
+			jobTaskSettings: resourceDatabricksJobExpandTaskSettings(taskElem),
+		}
+
+		for _, dependency := range expandStringList(taskElem["depends_on"].([]interface{})) {
+			task.DependsOn = append(task.DependsOn, jobTaskDependency{TaskKey: dependency})
+		}
+
+		if err := resourceDatabricksJobCheckTaskSettings(&task.jobTaskSettings, task.JobClusterKey); err != nil {
+			return nil, fmt.Errorf("task %s: %s", task.TaskKey, err)
+		}
+
+		result = append(result, task)
+	}
+
+	return result, nil
+}
+
+func resourceDatabricksJobFlattenTasks(tasks []jobTask) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(tasks))
+
+	for _, task := range tasks {
+		dependsOn := make([]string, 0, len(task.DependsOn))
+		for _, dependency := range task.DependsOn {
+			dependsOn = append(dependsOn, dependency.TaskKey)
+		}
+
+		taskElem := resourceDatabricksJobFlattenTaskSettings(&task.jobTaskSettings)
+		taskElem["task_key"] = task.TaskKey
+		taskElem["description"] = task.Description
+		taskElem["depends_on"] = dependsOn
+		taskElem["job_cluster_key"] = task.JobClusterKey
+
+		result = append(result, taskElem)
+	}
+
+	return result
+}
+
+// resourceDatabricksJobSortTasks orders tasks as the task blocks in current
+// (i.e., the ones in the state). The API does not guarantee the order in
+// which tasks are returned, so tasks that are not in current (e.g., when
+// importing) are appended sorted by key.
+func resourceDatabricksJobSortTasks(tasks []jobTask, current []interface{}) []jobTask {
+	keys := make([]string, 0, len(tasks))
+	byKey := make(map[string]jobTask, len(tasks))
+	for _, task := range tasks {
+		keys = append(keys, task.TaskKey)
+		byKey[task.TaskKey] = task
+	}
+
+	result := make([]jobTask, 0, len(tasks))
+	for _, key := range resourceDatabricksJobSortKeys(keys, current, "task_key") {
+		result = append(result, byKey[key])
+	}
+
+	return result
+}
+
+func resourceDatabricksJobExpandJobClusters(jobClusters []interface{}) []jobCluster {
+	result := make([]jobCluster, 0, len(jobClusters))
+
+	for _, v := range jobClusters {
+		jobClusterElem := v.(map[string]interface{})
+
+		spec := resourceDatabricksClusterExpandSpec(
+			jobClusterElem["new_cluster"].([]interface{})[0].(map[string]interface{}))
+
+		result = append(result, jobCluster{
+			JobClusterKey: jobClusterElem["job_cluster_key"].(string),
+			NewCluster:    &spec,
+		})
+	}
+
+	return result
+}
+
+func resourceDatabricksJobFlattenJobClusters(jobClusters []jobCluster) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(jobClusters))
+
+	for _, jobCluster := range jobClusters {
+		result = append(result, map[string]interface{}{
+			"job_cluster_key": jobCluster.JobClusterKey,
+			"new_cluster":     resourceDatabricksJobFlattenNewCluster(jobCluster.NewCluster),
+		})
+	}
+
+	return result
+}
+
+func resourceDatabricksJobSortJobClusters(jobClusters []jobCluster, current []interface{}) []jobCluster {
+	keys := make([]string, 0, len(jobClusters))
+	byKey := make(map[string]jobCluster, len(jobClusters))
+	for _, jobCluster := range jobClusters {
+		keys = append(keys, jobCluster.JobClusterKey)
+		byKey[jobCluster.JobClusterKey] = jobCluster
+	}
+
+	result := make([]jobCluster, 0, len(jobClusters))
+	for _, key := range resourceDatabricksJobSortKeys(keys, current, "job_cluster_key") {
+		result = append(result, byKey[key])
+	}
+
+	return result
+}
+
+// resourceDatabricksJobSortKeys returns keys ordered as the blocks in
+// current (identified by their keyName attribute), followed by the rest
+// of the keys sorted lexicographically.
+func resourceDatabricksJobSortKeys(keys []string, current []interface{}, keyName string) []string {
+	pending := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		pending[key] = true
+	}
+
+	result := make([]string, 0, len(keys))
+	for _, v := range current {
+		elem, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		key, _ := elem[keyName].(string)
+		if pending[key] {
+			result = append(result, key)
+			delete(pending, key)
+		}
+	}
+
+	remaining := make([]string, 0, len(pending))
+	for key := range pending {
+		remaining = append(remaining, key)
+	}
+	sort.Strings(remaining)
+
+	return append(result, remaining...)
+}
+
+func resourceDatabricksJobFlattenNewCluster(spec *clusterSpec) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if spec != nil {
+		result = append(result, resourceDatabricksClusterFlattenSpec(spec))
+	}
+	return result
+}
+
+func resourceDatabricksJobExpandNotebookTask(task []interface{}) *jobNotebookTask {
+	taskElem := task[0].(map[string]interface{})
+
+	result := jobNotebookTask{
+		NotebookPath: taskElem["notebook_path"].(string),
+	}
+
+	if v, ok := taskElem["base_parameters"]; ok {
+		result.BaseParameters = expandStringMap(v.(map[string]interface{}))
+	}
+
+	return &result
+}
+
+func resourceDatabricksJobFlattenNotebookTask(task *jobNotebookTask) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if task != nil {
+		result = append(result, map[string]interface{}{
+			"notebook_path":   task.NotebookPath,
+			"base_parameters": task.BaseParameters,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksJobExpandSparkJarTask(task []interface{}) *jobSparkJarTask {
+	taskElem := task[0].(map[string]interface{})
+
+	return &jobSparkJarTask{
+		JarUri:        taskElem["jar_uri"].(string),
+		MainClassName: taskElem["main_class_name"].(string),
+		Parameters:    expandStringList(taskElem["parameters"].([]interface{})),
+	}
+}
+
+func resourceDatabricksJobFlattenSparkJarTask(task *jobSparkJarTask) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if task != nil {
+		result = append(result, map[string]interface{}{
+			"jar_uri":         task.JarUri,
+			"main_class_name": task.MainClassName,
+			"parameters":      task.Parameters,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksJobExpandSparkPythonTask(task []interface{}) *jobSparkPythonTask {
+	taskElem := task[0].(map[string]interface{})
+
+	return &jobSparkPythonTask{
+		PythonFile: taskElem["python_file"].(string),
+		Parameters: expandStringList(taskElem["parameters"].([]interface{})),
+	}
+}
+
+func resourceDatabricksJobFlattenSparkPythonTask(task *jobSparkPythonTask) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if task != nil {
+		result = append(result, map[string]interface{}{
+			"python_file": task.PythonFile,
+			"parameters":  task.Parameters,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksJobExpandSparkSubmitTask(task []interface{}) *jobSparkSubmitTask {
+	result := jobSparkSubmitTask{}
+
+	// A task block without any attribute is read as a nil element.
+	if taskElem, ok := task[0].(map[string]interface{}); ok {
+		result.Parameters = expandStringList(taskElem["parameters"].([]interface{}))
+	}
+
+	return &result
+}
+
+func resourceDatabricksJobFlattenSparkSubmitTask(task *jobSparkSubmitTask) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if task != nil {
+		result = append(result, map[string]interface{}{
+			"parameters": task.Parameters,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksJobExpandSchedule(schedule []interface{}) *jobCronSchedule {
+	scheduleElem := schedule[0].(map[string]interface{})
+
+	return &jobCronSchedule{
+		QuartzCronExpression: scheduleElem["quartz_cron_expression"].(string),
+		TimezoneId:           scheduleElem["timezone_id"].(string),
+		PauseStatus:          scheduleElem["pause_status"].(string),
+	}
+}
+
+func resourceDatabricksJobFlattenSchedule(schedule *jobCronSchedule) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+	if schedule != nil {
+		result = append(result, map[string]interface{}{
+			"quartz_cron_expression": schedule.QuartzCronExpression,
+			"timezone_id":            schedule.TimezoneId,
+			"pause_status":           schedule.PauseStatus,
+		})
+	}
+	return result
+}
+
+func resourceDatabricksJobExpandEmailNotifications(notifications []interface{}) *jobEmailNotifications {
+	result := jobEmailNotifications{}
+
+	if notificationsElem, ok := notifications[0].(map[string]interface{}); ok {
+		result.OnStart = expandStringList(notificationsElem["on_start"].([]interface{}))
+		result.OnSuccess = expandStringList(notificationsElem["on_success"].([]interface{}))
+		result.OnFailure = expandStringList(notificationsElem["on_failure"].([]interface{}))
+		result.NoAlertForSkippedRuns = notificationsElem["no_alert_for_skipped_runs"].(bool)
+	}
+
+	return &result
+}
+
+func resourceDatabricksJobFlattenEmailNotifications(notifications *jobEmailNotifications) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0)
+
+	// The API returns an empty object when no notifications are configured.
+	if notifications != nil &&
+		(len(notifications.OnStart) > 0 ||
+			len(notifications.OnSuccess) > 0 ||
+			len(notifications.OnFailure) > 0 ||
+			notifications.NoAlertForSkippedRuns) {
+		result = append(result, map[string]interface{}{
+			"on_start":                  notifications.OnStart,
+			"on_success":                notifications.OnSuccess,
+			"on_failure":                notifications.OnFailure,
+			"no_alert_for_skipped_runs": notifications.NoAlertForSkippedRuns,
+		})
+	}
+
+	return result
+}
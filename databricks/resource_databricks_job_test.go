@@ -0,0 +1,795 @@
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/betabandido/databricks-sdk-go/models"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAccDatabricksJob_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksJobDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksJobConfig("tf-test-job", 1),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksJobExists("databricks_job.job"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "name", "tf-test-job"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "new_cluster.0.num_workers", "1"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "schedule.0.timezone_id", "UTC"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "max_retries", "2"),
+				),
+			},
+			{
+				Config: testAccDatabricksJobConfig("tf-test-job-renamed", 2),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksJobExists("databricks_job.job"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "name", "tf-test-job-renamed"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "new_cluster.0.num_workers", "2"),
+				),
+			},
+			{
+				ResourceName:      "databricks_job.job",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksJobExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		jobId, err := resourceDatabricksJobParseId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*Client).jobs
+
+		_, err = conn.Get(&jobsGetRequest{
+			JobId: jobId,
+		})
+
+		return err
+	}
+}
+
+func testAccCheckDatabricksJobDestroy(s *terraform.State) error {
+	endpoint := testAccProvider.Meta().(*Client).jobs
+
+	jobId, err := resourceDatabricksJobParseId(s.RootModule().Resources["databricks_job.job"].Primary.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = endpoint.Get(&jobsGetRequest{
+		JobId: jobId,
+	})
+
+	if err == nil {
+		return errors.New("job still exists")
+	}
+
+	if !resourceDatabricksJobNotExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func testAccDatabricksJobConfig(name string, numWorkers int) string {
+	const formatStr = `
+resource "databricks_job" "job" {
+	name = "%s"
+
+	new_cluster {
+		spark_version = "4.2.x-scala2.11"
+		node_type_id  = "Standard_D3_v2"
+		num_workers   = %d
+	}
+
+	notebook_task {
+		notebook_path = "%s/tf-test-notebook"
+
+		base_parameters = {
+			foo = "bar"
+		}
+	}
+
+	schedule {
+		quartz_cron_expression = "0 0 3 * * ?"
+		timezone_id            = "UTC"
+		pause_status           = "PAUSED"
+	}
+
+	max_retries     = 2
+	timeout_seconds = 3600
+}
+`
+	return fmt.Sprintf(formatStr, name, numWorkers, os.Getenv("DATABRICKS_WORKSPACE"))
+}
+
+func TestDatabricksJob_expandSettings(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksJob().Schema, map[string]interface{}{
+		"name":                "job",
+		"existing_cluster_id": "1234-567890-abc123",
+		"spark_python_task": []interface{}{
+			map[string]interface{}{
+				"python_file": "dbfs:/scripts/main.py",
+				"parameters":  []interface{}{"--verbose"},
+			},
+		},
+		"email_notifications": []interface{}{
+			map[string]interface{}{
+				"on_failure": []interface{}{"team@example.com"},
+			},
+		},
+		"max_concurrent_runs": 3,
+	})
+
+	settings, err := resourceDatabricksJobExpandSettings(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if settings.ExistingClusterId != "1234-567890-abc123" || settings.NewCluster != nil {
+		t.Fatalf("Wrong cluster settings: %+v", settings)
+	}
+
+	if settings.SparkPythonTask == nil ||
+		settings.SparkPythonTask.PythonFile != "dbfs:/scripts/main.py" ||
+		len(settings.SparkPythonTask.Parameters) != 1 {
+		t.Fatalf("Wrong python task: %+v", settings.SparkPythonTask)
+	}
+
+	if settings.EmailNotifications == nil || len(settings.EmailNotifications.OnFailure) != 1 {
+		t.Fatalf("Wrong email notifications: %+v", settings.EmailNotifications)
+	}
+
+	if settings.MaxConcurrentRuns != 3 {
+		t.Fatalf("Wrong max concurrent runs: %d", settings.MaxConcurrentRuns)
+	}
+}
+
+func TestDatabricksJob_expandSettingsRequiresTask(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksJob().Schema, map[string]interface{}{
+		"existing_cluster_id": "1234-567890-abc123",
+	})
+
+	if _, err := resourceDatabricksJobExpandSettings(d); err == nil {
+		t.Fatal("No error was returned for a job without a task")
+	}
+}
+
+func TestDatabricksJob_expandSettingsRequiresCluster(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksJob().Schema, map[string]interface{}{
+		"spark_submit_task": []interface{}{
+			map[string]interface{}{
+				"parameters": []interface{}{"--class", "Main"},
+			},
+		},
+	})
+
+	if _, err := resourceDatabricksJobExpandSettings(d); err == nil {
+		t.Fatal("No error was returned for a job without a cluster")
+	}
+}
+
+func TestDatabricksJob_flattenEmailNotificationsIgnoresEmptyObject(t *testing.T) {
+	if result := resourceDatabricksJobFlattenEmailNotifications(&jobEmailNotifications{}); len(result) != 0 {
+		t.Fatalf("Empty notifications were flattened: %v", result)
+	}
+}
+
+func TestDatabricksJob_handlesNonExistingJobError(t *testing.T) {
+	if resourceDatabricksJobNotExistsError(errors.New("an error")) {
+		t.Fatal("An error was incorrectly classified as non-existing-job error")
+	}
+
+	if !resourceDatabricksJobNotExistsError(client.NewError(
+		models.ErrorResponse{
+			ErrorCode: "INVALID_PARAMETER_VALUE",
+			Message:   "Job 42 does not exist.",
+		},
+		400,
+	)) {
+		t.Fatal("A non-existing-job error was not detected")
+	}
+}
+
+func TestAccDatabricksJob_multiTask(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksJobDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksJobMultiTaskConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksJobExists("databricks_job.job"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "task.#", "2"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "task.0.task_key", "ingest"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "task.1.depends_on.0", "ingest"),
+					resource.TestCheckResourceAttr(
+						"databricks_job.job", "job_cluster.0.job_cluster_key", "shared"),
+				),
+			},
+			{
+				ResourceName:      "databricks_job.job",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccDatabricksJobMultiTaskConfig() string {
+	const formatStr = `
+resource "databricks_job" "job" {
+	name = "tf-test-multi-task-job"
+
+	job_cluster {
+		job_cluster_key = "shared"
+
+		new_cluster {
+			spark_version = "4.2.x-scala2.11"
+			node_type_id  = "Standard_D3_v2"
+			num_workers   = 1
+		}
+	}
+
+	task {
+		task_key        = "ingest"
+		job_cluster_key = "shared"
+
+		notebook_task {
+			notebook_path = "%[1]s/tf-test-ingest"
+		}
+	}
+
+	task {
+		task_key        = "report"
+		depends_on      = ["ingest"]
+		job_cluster_key = "shared"
+
+		notebook_task {
+			notebook_path = "%[1]s/tf-test-report"
+		}
+	}
+}
+`
+	return fmt.Sprintf(formatStr, os.Getenv("DATABRICKS_WORKSPACE"))
+}
+
+func testDatabricksJobTask(key string, dependsOn ...string) map[string]interface{} {
+	deps := make([]interface{}, 0, len(dependsOn))
+	for _, dependency := range dependsOn {
+		deps = append(deps, dependency)
+	}
+
+	return map[string]interface{}{
+		"task_key":        key,
+		"depends_on":      deps,
+		"job_cluster_key": "",
+	}
+}
+
+func TestDatabricksJob_validateTasks(t *testing.T) {
+	jobClusters := []interface{}{
+		map[string]interface{}{"job_cluster_key": "shared"},
+	}
+
+	cases := []struct {
+		name  string
+		tasks []interface{}
+		valid bool
+	}{
+		{
+			name: "dag",
+			tasks: []interface{}{
+				testDatabricksJobTask("a"),
+				testDatabricksJobTask("b", "a"),
+				testDatabricksJobTask("c", "a", "b"),
+			},
+			valid: true,
+		},
+		{
+			name: "cycle",
+			tasks: []interface{}{
+				testDatabricksJobTask("a", "c"),
+				testDatabricksJobTask("b", "a"),
+				testDatabricksJobTask("c", "b"),
+			},
+		},
+		{
+			name: "self-dependency",
+			tasks: []interface{}{
+				testDatabricksJobTask("a", "a"),
+			},
+		},
+		{
+			name: "dangling dependency",
+			tasks: []interface{}{
+				testDatabricksJobTask("a"),
+				testDatabricksJobTask("b", "missing"),
+			},
+		},
+		{
+			name: "duplicate key",
+			tasks: []interface{}{
+				testDatabricksJobTask("a"),
+				testDatabricksJobTask("a"),
+			},
+		},
+		{
+			name: "unknown job cluster",
+			tasks: []interface{}{
+				map[string]interface{}{
+					"task_key":        "a",
+					"depends_on":      []interface{}{},
+					"job_cluster_key": "missing",
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		err := resourceDatabricksJobValidateTasks(c.tasks, jobClusters)
+		if c.valid && err != nil {
+			t.Fatalf("%s: unexpected error: %s", c.name, err)
+		}
+		if !c.valid && err == nil {
+			t.Fatalf("%s: no error was returned", c.name)
+		}
+	}
+}
+
+func TestDatabricksJob_sortTasksFollowsState(t *testing.T) {
+	tasks := []jobTask{
+		{TaskKey: "a"},
+		{TaskKey: "d"},
+		{TaskKey: "b"},
+		{TaskKey: "c"},
+	}
+
+	current := []interface{}{
+		map[string]interface{}{"task_key": "c"},
+		map[string]interface{}{"task_key": "removed"},
+		map[string]interface{}{"task_key": "a"},
+	}
+
+	sorted := resourceDatabricksJobSortTasks(tasks, current)
+
+	keys := make([]string, 0, len(sorted))
+	for _, task := range sorted {
+		keys = append(keys, task.TaskKey)
+	}
+
+	if strings.Join(keys, ",") != "c,a,b,d" {
+		t.Fatalf("Wrong task order: %v", keys)
+	}
+}
+
+func TestDatabricksJob_expandSettingsWithTasks(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksJob().Schema, map[string]interface{}{
+		"name": "job",
+		"job_cluster": []interface{}{
+			map[string]interface{}{
+				"job_cluster_key": "shared",
+				"new_cluster": []interface{}{
+					map[string]interface{}{
+						"spark_version": "4.2.x-scala2.11",
+						"node_type_id":  "Standard_D3_v2",
+						"num_workers":   2,
+					},
+				},
+			},
+		},
+		"task": []interface{}{
+			map[string]interface{}{
+				"task_key":        "ingest",
+				"job_cluster_key": "shared",
+				"notebook_task": []interface{}{
+					map[string]interface{}{
+						"notebook_path": "/ingest",
+					},
+				},
+			},
+			map[string]interface{}{
+				"task_key":            "report",
+				"depends_on":          []interface{}{"ingest"},
+				"existing_cluster_id": "1234-567890-abc123",
+				"spark_python_task": []interface{}{
+					map[string]interface{}{
+						"python_file": "dbfs:/report.py",
+					},
+				},
+			},
+		},
+	})
+
+	settings, err := resourceDatabricksJobExpandSettings(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !settings.isMultiTask() || len(settings.Tasks) != 2 {
+		t.Fatalf("Wrong tasks: %+v", settings.Tasks)
+	}
+
+	if settings.Tasks[1].DependsOn[0].TaskKey != "ingest" {
+		t.Fatalf("Wrong dependencies: %+v", settings.Tasks[1].DependsOn)
+	}
+
+	if len(settings.JobClusters) != 1 || settings.JobClusters[0].NewCluster.NumWorkers != 2 {
+		t.Fatalf("Wrong job clusters: %+v", settings.JobClusters)
+	}
+}
+
+func TestDatabricksJob_expandSettingsWithTasksKeepsJobSettings(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksJob().Schema, map[string]interface{}{
+		"name":            "job",
+		"timeout_seconds": 3600,
+		"email_notifications": []interface{}{
+			map[string]interface{}{
+				"on_failure": []interface{}{"team@example.com"},
+			},
+		},
+		"task": []interface{}{
+			map[string]interface{}{
+				"task_key":            "ingest",
+				"existing_cluster_id": "1234-567890-abc123",
+				"max_retries":         2,
+				"notebook_task": []interface{}{
+					map[string]interface{}{
+						"notebook_path": "/ingest",
+					},
+				},
+			},
+		},
+	})
+
+	settings, err := resourceDatabricksJobExpandSettings(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if settings.TimeoutSeconds != 3600 {
+		t.Fatalf("Wrong timeout: %d", settings.TimeoutSeconds)
+	}
+
+	if settings.EmailNotifications == nil || settings.EmailNotifications.OnFailure[0] != "team@example.com" {
+		t.Fatalf("Wrong email notifications: %+v", settings.EmailNotifications)
+	}
+
+	if settings.Tasks[0].MaxRetries != 2 {
+		t.Fatalf("Wrong task retries: %d", settings.Tasks[0].MaxRetries)
+	}
+}
+
+func TestDatabricksJob_retriesConflictWithTasks(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name":        "job",
+		"max_retries": 3,
+		"task": []interface{}{
+			map[string]interface{}{
+				"task_key":            "ingest",
+				"existing_cluster_id": "1234-567890-abc123",
+				"notebook_task": []interface{}{
+					map[string]interface{}{
+						"notebook_path": "/ingest",
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, errs := resourceDatabricksJob().Validate(terraform.NewResourceConfig(c))
+	if len(errs) == 0 {
+		t.Fatal("No error was returned for job-level retries in a multi-task job")
+	}
+}
+
+func TestDatabricksJob_migratesAwsAttributesSet(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "42",
+		Attributes: map[string]string{
+			"new_cluster.#":                                   "1",
+			"new_cluster.0.aws_attributes.#":                  "1",
+			"new_cluster.0.aws_attributes.2863404045.zone_id": "us-west-2a",
+		},
+	}
+
+	is, err := resourceDatabricksJob().MigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if is.Attributes["new_cluster.0.aws_attributes.0.zone_id"] != "us-west-2a" || len(is.Attributes) != 3 {
+		t.Fatalf("Wrong attributes: %v", is.Attributes)
+	}
+}
+
+func TestDatabricksJob_diffRequiresNodeTypeOrPool(t *testing.T) {
+	newCluster := []interface{}{
+		map[string]interface{}{
+			"spark_version": "4.2.x-scala2.11",
+			"num_workers":   1,
+		},
+	}
+	notebookTask := []interface{}{
+		map[string]interface{}{
+			"notebook_path": "/ingest",
+		},
+	}
+
+	cases := []struct {
+		raw      map[string]interface{}
+		expected string
+	}{
+		{
+			map[string]interface{}{
+				"new_cluster":   newCluster,
+				"notebook_task": notebookTask,
+			},
+			"new_cluster.0: node_type_id must be set for clusters without instance_pool_id",
+		},
+		{
+			map[string]interface{}{
+				"task": []interface{}{
+					map[string]interface{}{
+						"task_key":      "ingest",
+						"new_cluster":   newCluster,
+						"notebook_task": notebookTask,
+					},
+				},
+			},
+			"task.0.new_cluster.0: node_type_id must be set for clusters without instance_pool_id",
+		},
+		{
+			map[string]interface{}{
+				"job_cluster": []interface{}{
+					map[string]interface{}{
+						"job_cluster_key": "shared",
+						"new_cluster":     newCluster,
+					},
+				},
+				"task": []interface{}{
+					map[string]interface{}{
+						"task_key":        "ingest",
+						"job_cluster_key": "shared",
+						"notebook_task":   notebookTask,
+					},
+				},
+			},
+			"job_cluster.0.new_cluster.0: node_type_id must be set for clusters without instance_pool_id",
+		},
+	}
+
+	for _, c := range cases {
+		c.raw["name"] = "job"
+
+		cfg, err := config.NewRawConfig(c.raw)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		_, err = resourceDatabricksJob().Diff(nil, terraform.NewResourceConfig(cfg), nil)
+		if err == nil || err.Error() != c.expected {
+			t.Fatalf("Wrong error: %v", err)
+		}
+	}
+}
+
+func TestDatabricksJob_diffRequiresSingleUserName(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name": "job",
+		"new_cluster": []interface{}{
+			map[string]interface{}{
+				"spark_version":      "4.2.x-scala2.11",
+				"node_type_id":       "Standard_D3_v2",
+				"num_workers":        1,
+				"data_security_mode": "SINGLE_USER",
+			},
+		},
+		"notebook_task": []interface{}{
+			map[string]interface{}{
+				"notebook_path": "/ingest",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = resourceDatabricksJob().Diff(nil, terraform.NewResourceConfig(c), nil)
+	if err == nil || err.Error() != "new_cluster.0: single_user_name must be set when data_security_mode is SINGLE_USER" {
+		t.Fatalf("Wrong error: %v", err)
+	}
+}
+
+func TestDatabricksJob_readKeepsDockerPasswords(t *testing.T) {
+	dockerImage := func() *clusterDockerImage {
+		return &clusterDockerImage{
+			Url:       "registry.example.com/runtime:latest",
+			BasicAuth: &clusterDockerBasicAuth{Username: "robot"},
+		}
+	}
+
+	handler := func(path string, body []byte) interface{} {
+		return jobsGetResponse{
+			JobId: 42,
+			Settings: &jobSettings{
+				Name: "job",
+				JobClusters: []jobCluster{
+					{
+						JobClusterKey: "shared",
+						NewCluster: &clusterSpec{
+							SparkVersion: "4.2.x-scala2.11",
+							NodeTypeId:   "Standard_D3_v2",
+							NumWorkers:   1,
+							DockerImage:  dockerImage(),
+						},
+					},
+				},
+				Tasks: []jobTask{
+					{
+						TaskKey: "ingest",
+						jobTaskSettings: jobTaskSettings{
+							NewCluster: &clusterSpec{
+								SparkVersion: "4.2.x-scala2.11",
+								NodeTypeId:   "Standard_D3_v2",
+								NumWorkers:   1,
+								DockerImage:  dockerImage(),
+							},
+							NotebookTask: &jobNotebookTask{NotebookPath: "/ingest"},
+						},
+					},
+				},
+				Format: jobFormatMultiTask,
+			},
+		}
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	state, err := resourceDatabricksJob().Refresh(
+		&terraform.InstanceState{
+			ID: "42",
+			Attributes: map[string]string{
+				"name":                          "job",
+				"job_cluster.#":                 "1",
+				"job_cluster.0.job_cluster_key": "shared",
+				"job_cluster.0.new_cluster.#":   "1",
+				"job_cluster.0.new_cluster.0.docker_image.#":                       "1",
+				"job_cluster.0.new_cluster.0.docker_image.0.basic_auth.#":          "1",
+				"job_cluster.0.new_cluster.0.docker_image.0.basic_auth.0.password": "cluster-secret",
+				"task.#":                              "1",
+				"task.0.task_key":                     "ingest",
+				"task.0.new_cluster.#":                "1",
+				"task.0.new_cluster.0.docker_image.#": "1",
+				"task.0.new_cluster.0.docker_image.0.basic_auth.#":          "1",
+				"task.0.new_cluster.0.docker_image.0.basic_auth.0.password": "task-secret",
+			},
+		},
+		&Client{jobs: &jobsEndpoint{Client: cl}},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if state.Attributes["job_cluster.0.new_cluster.0.docker_image.0.basic_auth.0.password"] != "cluster-secret" ||
+		state.Attributes["task.0.new_cluster.0.docker_image.0.basic_auth.0.password"] != "task-secret" {
+		t.Fatalf("Passwords were not kept: %v", state.Attributes)
+	}
+}
+
+func TestDatabricksJob_readSetsNewCluster(t *testing.T) {
+	handler := func(path string, body []byte) interface{} {
+		return jobsGetResponse{
+			JobId: 42,
+			Settings: &jobSettings{
+				Name: "job",
+				jobTaskSettings: jobTaskSettings{
+					NewCluster: &clusterSpec{
+						SparkVersion: "4.2.x-scala2.11",
+						NodeTypeId:   "Standard_D3_v2",
+						Autoscale: &models.ClustersAutoScale{
+							MinWorkers: 1,
+							MaxWorkers: 4,
+						},
+					},
+					NotebookTask: &jobNotebookTask{NotebookPath: "/ingest"},
+				},
+			},
+		}
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	state, err := resourceDatabricksJob().Refresh(
+		&terraform.InstanceState{
+			ID:         "42",
+			Attributes: map[string]string{"name": "job"},
+		},
+		&Client{jobs: &jobsEndpoint{Client: cl}},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if state.Attributes["new_cluster.0.spark_version"] != "4.2.x-scala2.11" ||
+		state.Attributes["new_cluster.0.autoscale.#"] != "1" {
+		t.Fatalf("Wrong state: %v", state.Attributes)
+	}
+}
+
+func TestDatabricksJob_diffReplacesJobWhenTasksAreRemoved(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":                                   "1",
+			"name":                                 "job",
+			"task.#":                               "1",
+			"task.0.task_key":                      "ingest",
+			"task.0.existing_cluster_id":           "0123-456789-abc123",
+			"task.0.notebook_task.#":               "1",
+			"task.0.notebook_task.0.notebook_path": "/ingest",
+		},
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name":                "job",
+		"existing_cluster_id": "0123-456789-abc123",
+		"notebook_task": []interface{}{
+			map[string]interface{}{
+				"notebook_path": "/ingest",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	diff, err := resourceDatabricksJob().Diff(state, terraform.NewResourceConfig(c), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !diff.RequiresNew() {
+		t.Fatalf("Removing the tasks does not replace the job: %v", diff)
+	}
+}
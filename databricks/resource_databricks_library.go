@@ -0,0 +1,149 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"time"
+)
+
+// resourceDatabricksLibrary installs a single library on an existing cluster.
+// Only this library is read and uninstalled, so it can be used on clusters
+// whose other libraries are managed elsewhere.
+func resourceDatabricksLibrary() *schema.Resource {
+	s := resourceDatabricksLibraryForceNew(resourceDatabricksClusterLibraryResource().Schema)
+
+	s["cluster_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	}
+
+	return &schema.Resource{
+		Create: resourceDatabricksLibraryCreate,
+		Read:   resourceDatabricksLibraryRead,
+		Delete: resourceDatabricksLibraryDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: s,
+	}
+}
+
+// resourceDatabricksLibraryForceNew makes all the attributes (including the
+// nested ones) force a new resource, as libraries cannot be updated.
+func resourceDatabricksLibraryForceNew(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for _, v := range s {
+		v.ForceNew = true
+		if elem, ok := v.Elem.(*schema.Resource); ok {
+			resourceDatabricksLibraryForceNew(elem.Schema)
+		}
+	}
+	return s
+}
+
+func resourceDatabricksLibraryCreate(d *schema.ResourceData, m interface{}) error {
+	clusterId := d.Get("cluster_id").(string)
+
+	l, err := resourceDatabricksLibraryExpand(d)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Installing library on cluster: %s", clusterId)
+
+	err = resourceDatabricksClusterInstallLibraries(m, clusterId, []library{*l}, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resourceDatabricksLibraryBuildId(clusterId, *l))
+
+	log.Printf("[DEBUG] Library ID: %s", d.Id())
+
+	return resourceDatabricksLibraryRead(d, m)
+}
+
+func resourceDatabricksLibraryRead(d *schema.ResourceData, m interface{}) error {
+	clusterId := d.Get("cluster_id").(string)
+
+	l, err := resourceDatabricksLibraryExpand(d)
+	if err != nil {
+		return err
+	}
+
+	info, err := m.(*Client).clusters.Get(&clustersIdRequest{
+		ClusterId: clusterId,
+	})
+	if err != nil {
+		if resourceDatabricksClusterNotExistsError(err) {
+			log.Printf("[WARN] Library (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// As with the libraries of the cluster resource, the library can only be
+	// checked while the cluster runs.
+	if info.State != models.RUNNING {
+		return nil
+	}
+
+	installed, err := resourceDatabricksClusterInstalledLibraries(m, clusterId)
+	if err != nil {
+		return err
+	}
+
+	if len(resourceDatabricksClusterIntersectLibraries([]library{*l}, installed)) == 0 {
+		log.Printf("[WARN] Library (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceDatabricksLibraryDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).libraries
+
+	log.Printf("[DEBUG] Uninstalling library: %s", d.Id())
+
+	l, err := resourceDatabricksLibraryExpand(d)
+	if err != nil {
+		return err
+	}
+
+	err = apiClient.Uninstall(&librariesRequest{
+		ClusterId: d.Get("cluster_id").(string),
+		Libraries: []library{*l},
+	})
+	if err != nil && !resourceDatabricksClusterNotExistsError(err) {
+		return err
+	}
+
+	log.Printf("[WARN] Library (%s) is uninstalled when its cluster restarts", d.Id())
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksLibraryExpand(d *schema.ResourceData) (*library, error) {
+	return resourceDatabricksClusterExpandLibrary(map[string]interface{}{
+		"jar":   d.Get("jar"),
+		"egg":   d.Get("egg"),
+		"whl":   d.Get("whl"),
+		"pypi":  d.Get("pypi"),
+		"maven": d.Get("maven"),
+		"cran":  d.Get("cran"),
+	})
+}
+
+// resourceDatabricksLibraryBuildId builds the ID from the whole library, so
+// that libraries only differing in, e.g., their repo get different IDs.
+func resourceDatabricksLibraryBuildId(clusterId string, l library) string {
+	return clusterId + secretIdSeparator + resourceDatabricksClusterLibraryKey(l)
+}
@@ -0,0 +1,191 @@
+package databricks
+
+import (
+	"fmt"
+	"github.com/betabandido/databricks-sdk-go/models"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"reflect"
+	"testing"
+)
+
+func TestAccDatabricksLibrary_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksLibraryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksLibraryConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksLibraryExists("databricks_library.library"),
+					resource.TestCheckResourceAttr(
+						"databricks_library.library", "pypi.0.package", "simplejson==3.8.0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksLibraryExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		installed, err := resourceDatabricksClusterInstalledLibraries(
+			testAccProvider.Meta(),
+			rs.Primary.Attributes["cluster_id"],
+		)
+		if err != nil {
+			return err
+		}
+
+		expected := library{Pypi: &libraryPythonPyPi{Package: rs.Primary.Attributes["pypi.0.package"]}}
+		if len(resourceDatabricksClusterIntersectLibraries([]library{expected}, installed)) == 0 {
+			return fmt.Errorf("library is not installed: %s", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckDatabricksLibraryDestroy(s *terraform.State) error {
+	rs := s.RootModule().Resources["databricks_library.library"]
+
+	installed, err := resourceDatabricksClusterInstalledLibraries(
+		testAccProvider.Meta(),
+		rs.Primary.Attributes["cluster_id"],
+	)
+	if err != nil {
+		if resourceDatabricksClusterNotExistsError(err) {
+			return nil
+		}
+		return err
+	}
+
+	expected := library{Pypi: &libraryPythonPyPi{Package: rs.Primary.Attributes["pypi.0.package"]}}
+	if len(resourceDatabricksClusterIntersectLibraries([]library{expected}, installed)) > 0 {
+		return fmt.Errorf("library still installed: %s", rs.Primary.ID)
+	}
+
+	return nil
+}
+
+func testAccDatabricksLibraryConfig() string {
+	return `
+resource "databricks_cluster" "cluster" {
+	name                    = "tf-test-library"
+	spark_version           = "4.2.x-scala2.11"
+	node_type_id            = "Standard_D3_v2"
+	num_workers             = 1
+	autotermination_minutes = 10
+	permanently_delete      = true
+}
+
+resource "databricks_library" "library" {
+	cluster_id = "${databricks_cluster.cluster.id}"
+
+	pypi {
+		package = "simplejson==3.8.0"
+	}
+}
+`
+}
+
+func testDatabricksLibraryData(t *testing.T) *schema.ResourceData {
+	d := schema.TestResourceDataRaw(t, resourceDatabricksLibrary().Schema, map[string]interface{}{
+		"cluster_id": "0123-456789-abc123",
+		"whl":        "dbfs:/wheels/ours-1.0-py3-none-any.whl",
+	})
+	d.SetId(`0123-456789-abc123|||{"whl":"dbfs:/wheels/ours-1.0-py3-none-any.whl"}`)
+	return d
+}
+
+func TestDatabricksLibrary_createInstallsLibrary(t *testing.T) {
+	api := &testDatabricksClusterApi{info: testDatabricksClusterInfo(models.RUNNING)}
+
+	cl, closeServer := testDatabricksServer(t, api.handle)
+	defer closeServer()
+
+	d := testDatabricksLibraryData(t)
+	d.SetId("")
+
+	err := resourceDatabricksLibraryCreate(d, testDatabricksClusterClient(cl))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if d.Id() != `0123-456789-abc123|||{"whl":"dbfs:/wheels/ours-1.0-py3-none-any.whl"}` {
+		t.Fatalf("Wrong ID: %s", d.Id())
+	}
+
+	if !reflect.DeepEqual(api.calls, []string{"libraries/install"}) {
+		t.Fatalf("Wrong calls: %v", api.calls)
+	}
+}
+
+func TestDatabricksLibrary_readRemovesUninstalledLibrary(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+		libraries: []libraryFullStatus{
+			{Library: &library{Jar: "dbfs:/jars/owner.jar"}, Status: "INSTALLED"},
+			{Library: &library{Whl: "dbfs:/wheels/ours-1.0-py3-none-any.whl"}, Status: "UNINSTALL_ON_RESTART"},
+		},
+	}
+
+	cl, closeServer := testDatabricksServer(t, api.handle)
+	defer closeServer()
+
+	d := testDatabricksLibraryData(t)
+
+	err := resourceDatabricksLibraryRead(d, testDatabricksClusterClient(cl))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if d.Id() != "" {
+		t.Fatalf("Library was not removed from state: %s", d.Id())
+	}
+}
+
+func TestDatabricksLibrary_deleteOnlyUninstallsItsLibrary(t *testing.T) {
+	api := &testDatabricksClusterApi{
+		info: testDatabricksClusterInfo(models.RUNNING),
+		libraries: []libraryFullStatus{
+			{Library: &library{Jar: "dbfs:/jars/owner.jar"}, Status: "INSTALLED"},
+			{Library: &library{Whl: "dbfs:/wheels/ours-1.0-py3-none-any.whl"}, Status: "INSTALLED"},
+		},
+	}
+
+	cl, closeServer := testDatabricksServer(t, api.handle)
+	defer closeServer()
+
+	err := resourceDatabricksLibraryDelete(testDatabricksLibraryData(t), testDatabricksClusterClient(cl))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if api.libraries[0].Status != "INSTALLED" || api.libraries[1].Status != "UNINSTALL_ON_RESTART" {
+		t.Fatalf("Wrong libraries: %+v", api.libraries)
+	}
+}
+
+func TestDatabricksLibrary_idIncludesWholeLibrary(t *testing.T) {
+	id := resourceDatabricksLibraryBuildId("0123-456789-abc123", library{
+		Pypi: &libraryPythonPyPi{Package: "simplejson==3.8.0"},
+	})
+	idWithRepo := resourceDatabricksLibraryBuildId("0123-456789-abc123", library{
+		Pypi: &libraryPythonPyPi{Package: "simplejson==3.8.0", Repo: "https://pypi.example.com"},
+	})
+
+	if id == idWithRepo {
+		t.Fatalf("Libraries with different repos have the same ID: %s", id)
+	}
+}
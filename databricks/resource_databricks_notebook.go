@@ -1,12 +1,18 @@
 package databricks
 
 import (
+	"crypto/md5"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/betabandido/databricks-sdk-go/client"
 	"github.com/betabandido/databricks-sdk-go/models"
 	"github.com/hashicorp/terraform/helper/schema"
+	"io/ioutil"
 	"log"
+	"path/filepath"
 	"strings"
 )
 
@@ -17,19 +23,48 @@ func resourceDatabricksNotebook() *schema.Resource {
 		Update: resourceDatabricksNotebookUpdate,
 		Delete: resourceDatabricksNotebookDelete,
 
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceDatabricksNotebookCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"path": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
+			// The language and format of notebooks given as a local file are
+			// inferred from its extension, unless they are set.
 			"language": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice([]string{"SCALA", "PYTHON", "SQL", "R"}),
+			},
+			"format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInSlice([]string{"SOURCE", "JUPYTER", "DBC", "HTML"}),
 			},
 			"content": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ConflictsWith:    []string{"source"},
+				DiffSuppressFunc: resourceDatabricksNotebookSuppressEquivalentContent,
+			},
+			// Notebooks given as a local file are only tracked through the
+			// hash of their content, which keeps the state and plans small.
+			"source": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"content"},
+			},
+			"md5": {
 				Type:     schema.TypeString,
-				Required: true,
+				Computed: true,
 			},
 		},
 	}
@@ -41,20 +76,25 @@ func resourceDatabricksNotebookCreate(d *schema.ResourceData, m interface{}) err
 	log.Print("[DEBUG] Creating notebook")
 
 	path := d.Get("path").(string)
-	language := models.WorkspaceLanguage(d.Get("language").(string))
-	content := d.Get("content").(string)
 
-	err := apiClient.Import(&models.WorkspaceImportRequest{
-		Path:     path,
-		Language: &language,
-		Content:  content,
-	})
+	request, err := resourceDatabricksNotebookImportRequest(d)
+	if err != nil {
+		return err
+	}
+
+	request.Path = path
+
+	err = apiClient.Import(request)
 	if err != nil {
 		return err
 	}
 
 	d.SetId(path)
 
+	if err := resourceDatabricksNotebookSetMd5(d, request); err != nil {
+		return err
+	}
+
 	log.Printf("[DEBUG] Notebook ID: %s", d.Id())
 
 	return nil
@@ -63,14 +103,47 @@ func resourceDatabricksNotebookCreate(d *schema.ResourceData, m interface{}) err
 func resourceDatabricksNotebookRead(d *schema.ResourceData, m interface{}) error {
 	apiClient := m.(*Client).workspace
 
-	format := models.SOURCE
+	status, err := apiClient.GetStatus(&models.WorkspaceGetStatusRequest{
+		Path: d.Id(),
+	})
+	if err != nil {
+		if resourceDatabricksNotebookNotExistsError(err) {
+			log.Printf("[WARN] Notebook (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	if status.ObjectType == nil || *status.ObjectType != models.NOTEBOOK {
+		return fmt.Errorf("object at %s is not a notebook", d.Id())
+	}
+
+	// Notebooks that were imported have no format yet, and are compared in
+	// the default one.
+	format := models.WorkspaceExportFormat(d.Get("format").(string))
+	if format == "" {
+		format = models.SOURCE
+	}
+
+	d.Set("path", d.Id())
+	if status.Language != nil {
+		d.Set("language", string(*status.Language))
+	}
+	d.Set("format", string(format))
+
+	// DBC archives and HTML pages are not exported the same way they were
+	// imported, so their content cannot be compared.
+	if format == models.DBC || format == models.HTML {
+		return nil
+	}
 
 	resp, err := apiClient.Export(&models.WorkspaceExportRequest{
 		Path:   d.Id(),
 		Format: &format,
 	})
 	if err != nil {
-		if databricksError, ok := err.(client.Error); ok && databricksError.Code() == "RESOURCE_DOES_NOT_EXIST" {
+		if resourceDatabricksNotebookNotExistsError(err) {
 			log.Printf("[WARN] Notebook (%s) not found, removing from state", d.Id())
 			d.SetId("")
 			return nil
@@ -78,32 +151,360 @@ func resourceDatabricksNotebookRead(d *schema.ResourceData, m interface{}) error
 		return err
 	}
 
-	content, err := resourceDatabricksNotebookSanitizeContent(resp.Content)
+	var language models.WorkspaceLanguage
+	if status.Language != nil {
+		language = *status.Language
+	}
+
+	content := resp.Content
+	if format == models.SOURCE {
+		normalized, err := resourceDatabricksNotebookNormalizeContent(resp.Content, language)
+		if err != nil {
+			return err
+		}
+		content = normalized
+	}
+
+	hash, err := resourceDatabricksNotebookMd5(content, format, language)
 	if err != nil {
 		return err
 	}
 
-	d.Set("content", *content)
+	// The exported content is only kept if it differs from the configured
+	// one, as it may be formatted differently (e.g., Jupyter metadata).
+	if d.Get("source").(string) == "" {
+		configured, err := resourceDatabricksNotebookMd5(d.Get("content").(string), format, language)
+		if err != nil || configured != hash {
+			d.Set("content", content)
+		}
+	}
+	d.Set("md5", hash)
 
 	return nil
 }
 
-func resourceDatabricksNotebookSanitizeContent(content string) (*string, error) {
-	decoded, err := base64.StdEncoding.DecodeString(content)
+// resourceDatabricksNotebookCustomizeDiff compares the hash of the local
+// source file with the one of the notebook, so that changes to the file show
+// up as a change of md5.
+func resourceDatabricksNotebookCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("source") {
+		return d.SetNewComputed("md5")
+	}
+
+	source := d.Get("source").(string)
+	if source == "" {
+		return nil
+	}
+
+	content, err := resourceDatabricksNotebookReadSource(source)
+	if err != nil {
+		return err
+	}
+
+	format, err := resourceDatabricksNotebookDiffInferred(d, "format", func(source string) string {
+		return string(resourceDatabricksNotebookInferFormat("", source))
+	})
+	if err != nil {
+		return err
+	}
+
+	language, err := resourceDatabricksNotebookDiffInferred(d, "language", func(source string) string {
+		return string(resourceDatabricksNotebookInferLanguage("", source))
+	})
+	if err != nil {
+		return err
+	}
+
+	hash, err := resourceDatabricksNotebookMd5(
+		content,
+		resourceDatabricksNotebookInferFormat(format, source),
+		resourceDatabricksNotebookInferLanguage(language, source),
+	)
+	if err != nil {
+		return err
+	}
+
+	if hash != d.Get("md5").(string) {
+		return d.SetNew("md5", hash)
+	}
+
+	return nil
+}
+
+// resourceDatabricksNotebookDiffInferred infers again the given attribute when
+// the source changes, as long as its value was inferred from the previous
+// source. Otherwise, a notebook moved from a .py to a .scala file would keep
+// the language of the former. A configured value cannot be told apart from an
+// inferred one, so the same applies to values set to what would be inferred.
+func resourceDatabricksNotebookDiffInferred(d *schema.ResourceDiff, key string, infer func(string) string) (string, error) {
+	value := d.Get(key).(string)
+
+	if !d.HasChange("source") || d.HasChange(key) {
+		return value, nil
+	}
+
+	oldSource, newSource := d.GetChange("source")
+	if value == "" || value != infer(oldSource.(string)) {
+		return value, nil
+	}
+
+	// Values that cannot be inferred from the new source are kept.
+	inferred := infer(newSource.(string))
+	if inferred == "" || inferred == value {
+		return value, nil
+	}
+
+	return inferred, d.SetNew(key, inferred)
+}
+
+// resourceDatabricksNotebookContent returns the base64-encoded content of the
+// notebook, which is either given inline or as a local file.
+func resourceDatabricksNotebookContent(d *schema.ResourceData) (string, error) {
+	if source := d.Get("source").(string); source != "" {
+		return resourceDatabricksNotebookReadSource(source)
+	}
+
+	if content := d.Get("content").(string); content != "" {
+		return content, nil
+	}
+
+	return "", errors.New("either source or content must be set")
+}
+
+func resourceDatabricksNotebookReadSource(source string) (string, error) {
+	content, err := ioutil.ReadFile(source)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(content), nil
+}
+
+// resourceDatabricksNotebookImportRequest builds the request to import the
+// configured notebook, inferring its language and format if needed.
+func resourceDatabricksNotebookImportRequest(d *schema.ResourceData) (*models.WorkspaceImportRequest, error) {
+	source := d.Get("source").(string)
+
+	content, err := resourceDatabricksNotebookContent(d)
 	if err != nil {
 		return nil, err
 	}
 
-	lines := strings.Split(string(decoded), "\n")
-	if !strings.Contains(lines[0], "Databricks notebook source") {
-		return nil, fmt.Errorf("notebook starts with unexpected text: %s", lines[0])
+	format := resourceDatabricksNotebookInferFormat(d.Get("format").(string), source)
+	language := resourceDatabricksNotebookInferLanguage(d.Get("language").(string), source)
+
+	request := &models.WorkspaceImportRequest{
+		Format:  &format,
+		Content: content,
+	}
+
+	// The language is only needed for notebooks in source format, as the
+	// other formats include it.
+	if language != "" {
+		request.Language = &language
+	} else if format == models.SOURCE {
+		return nil, errors.New("language must be set when it cannot be inferred from source")
+	}
+
+	d.Set("format", string(format))
+	if language != "" {
+		d.Set("language", string(language))
+	}
+
+	return request, nil
+}
+
+func resourceDatabricksNotebookInferFormat(format string, source string) models.WorkspaceExportFormat {
+	if format != "" {
+		return models.WorkspaceExportFormat(format)
+	}
+
+	switch strings.ToLower(filepath.Ext(source)) {
+	case ".ipynb":
+		return models.JUPYTER
+	case ".dbc":
+		return models.DBC
+	case ".html":
+		return models.HTML
+	default:
+		return models.SOURCE
+	}
+}
+
+func resourceDatabricksNotebookInferLanguage(language string, source string) models.WorkspaceLanguage {
+	if language != "" {
+		return models.WorkspaceLanguage(language)
+	}
+
+	switch strings.ToLower(filepath.Ext(source)) {
+	case ".py", ".ipynb":
+		return models.PYTHON
+	case ".scala":
+		return models.SCALA
+	case ".sql":
+		return models.SQL
+	case ".r":
+		return models.R
+	default:
+		return ""
+	}
+}
+
+func resourceDatabricksNotebookSetMd5(d *schema.ResourceData, request *models.WorkspaceImportRequest) error {
+	var language models.WorkspaceLanguage
+	if request.Language != nil {
+		language = *request.Language
 	}
 
-	sanitized := strings.Join(lines[1:], "\n")
+	hash, err := resourceDatabricksNotebookMd5(request.Content, *request.Format, language)
+	if err != nil {
+		return err
+	}
 
-	content = base64.StdEncoding.EncodeToString([]byte(sanitized))
+	d.Set("md5", hash)
 
-	return &content, nil
+	return nil
+}
+
+// resourceDatabricksNotebookMd5 returns the hash of the base64-encoded
+// content. Notebooks in source format are hashed once normalized, and Jupyter
+// notebooks are hashed through the type and source of their cells, ignoring
+// metadata and outputs. Other formats are hashed as they are.
+func resourceDatabricksNotebookMd5(
+	content string,
+	format models.WorkspaceExportFormat,
+	language models.WorkspaceLanguage,
+) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", err
+	}
+
+	switch format {
+	case models.SOURCE:
+		decoded = []byte(resourceDatabricksNotebookNormalize(string(decoded), language))
+	case models.JUPYTER:
+		cells, err := resourceDatabricksNotebookJupyterCells(decoded)
+		if err != nil {
+			return "", err
+		}
+		decoded = []byte(cells)
+	}
+
+	hash := md5.Sum(decoded)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func resourceDatabricksNotebookNotExistsError(err error) bool {
+	databricksError, ok := err.(client.Error)
+	return ok && databricksError.Code() == "RESOURCE_DOES_NOT_EXIST"
+}
+
+// resourceDatabricksNotebookCommentPrefixes are the comment prefixes used in
+// the source of notebooks, which mark their header, cell separators and magic
+// commands.
+var resourceDatabricksNotebookCommentPrefixes = map[models.WorkspaceLanguage]string{
+	models.PYTHON: "#",
+	models.R:      "#",
+	models.SCALA:  "//",
+	models.SQL:    "--",
+}
+
+// resourceDatabricksNotebookNormalizeContent normalizes base64-encoded
+// notebook source (see resourceDatabricksNotebookNormalize).
+func resourceDatabricksNotebookNormalizeContent(content string, language models.WorkspaceLanguage) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", err
+	}
+
+	normalized := resourceDatabricksNotebookNormalize(string(decoded), language)
+
+	return base64.StdEncoding.EncodeToString([]byte(normalized)), nil
+}
+
+// resourceDatabricksNotebookNormalize removes the differences between the
+// source of a notebook and the way Databricks exports it: line endings,
+// trailing whitespace, the "Databricks notebook source" header and the blank
+// lines around cell separators ("COMMAND ----------"). If the language is not
+// known, the comment prefixes of all the languages are recognized.
+func resourceDatabricksNotebookNormalize(source string, language models.WorkspaceLanguage) string {
+	var prefixes []string
+	if prefix, ok := resourceDatabricksNotebookCommentPrefixes[language]; ok {
+		prefixes = []string{prefix}
+	} else {
+		prefixes = []string{"#", "//", "--"}
+	}
+
+	isComment := func(line string, text string) bool {
+		for _, prefix := range prefixes {
+			if line == prefix+" "+text {
+				return true
+			}
+		}
+		return false
+	}
+
+	source = strings.Replace(source, "\r\n", "\n", -1)
+	source = strings.Replace(source, "\r", "\n", -1)
+
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	if len(lines) > 0 {
+		if isComment(strings.TrimSpace(lines[0]), "Databricks notebook source") {
+			lines = lines[1:]
+		}
+	}
+
+	// Leading blank lines are dropped as if they followed a separator.
+	result := make([]string, 0, len(lines))
+	afterSeparator := true
+	for _, line := range lines {
+		if isComment(line, "COMMAND ----------") {
+			for len(result) > 0 && result[len(result)-1] == "" {
+				result = result[:len(result)-1]
+			}
+			result = append(result, line)
+			afterSeparator = true
+			continue
+		}
+
+		if line == "" && afterSeparator {
+			continue
+		}
+
+		result = append(result, line)
+		afterSeparator = false
+	}
+
+	for len(result) > 0 && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// resourceDatabricksNotebookSuppressEquivalentContent ignores differences
+// between inline contents that only differ in their formatting (see
+// resourceDatabricksNotebookNormalize).
+func resourceDatabricksNotebookSuppressEquivalentContent(k, old, new string, d *schema.ResourceData) bool {
+	language := models.WorkspaceLanguage(d.Get("language").(string))
+
+	oldNormalized, err := resourceDatabricksNotebookNormalizeContent(old, language)
+	if err != nil {
+		return false
+	}
+
+	newNormalized, err := resourceDatabricksNotebookNormalizeContent(new, language)
+	if err != nil {
+		return false
+	}
+
+	return oldNormalized == newNormalized
 }
 
 func resourceDatabricksNotebookUpdate(d *schema.ResourceData, m interface{}) error {
@@ -111,15 +512,20 @@ func resourceDatabricksNotebookUpdate(d *schema.ResourceData, m interface{}) err
 
 	log.Printf("[DEBUG] Updating notebook: %s", d.Id())
 
-	language := models.WorkspaceLanguage(d.Get("language").(string))
-	content := d.Get("content").(string)
+	request, err := resourceDatabricksNotebookImportRequest(d)
+	if err != nil {
+		return err
+	}
 
-	return apiClient.Import(&models.WorkspaceImportRequest{
-		Path:      d.Id(),
-		Language:  &language,
-		Content:   content,
-		Overwrite: true,
-	})
+	request.Path = d.Id()
+	request.Overwrite = true
+
+	err = apiClient.Import(request)
+	if err != nil {
+		return err
+	}
+
+	return resourceDatabricksNotebookSetMd5(d, request)
 }
 
 func resourceDatabricksNotebookDelete(d *schema.ResourceData, m interface{}) error {
@@ -138,3 +544,37 @@ func resourceDatabricksNotebookDelete(d *schema.ResourceData, m interface{}) err
 
 	return nil
 }
+
+// resourceDatabricksNotebookJupyterCells returns the type and source of the
+// cells of a Jupyter notebook, whose sources are either strings or lists of
+// lines.
+func resourceDatabricksNotebookJupyterCells(notebook []byte) (string, error) {
+	var parsed struct {
+		Cells []struct {
+			CellType string      `json:"cell_type"`
+			Source   interface{} `json:"source"`
+		} `json:"cells"`
+	}
+
+	if err := json.Unmarshal(notebook, &parsed); err != nil {
+		return "", fmt.Errorf("invalid Jupyter notebook: %s", err)
+	}
+
+	cells := make([]string, 0, len(parsed.Cells))
+	for _, cell := range parsed.Cells {
+		var source string
+		switch v := cell.Source.(type) {
+		case string:
+			source = v
+		case []interface{}:
+			for _, line := range v {
+				if s, ok := line.(string); ok {
+					source += s
+				}
+			}
+		}
+		cells = append(cells, cell.CellType+"\n"+source)
+	}
+
+	return strings.Join(cells, "\n\n"), nil
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"github.com/betabandido/databricks-sdk-go/client"
 	"github.com/betabandido/databricks-sdk-go/models"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -27,6 +30,11 @@ func TestAccDatabricksNotebook_basic(t *testing.T) {
 				{
 					Config: testAccDatabricksNotebookConfig(language),
 				},
+				{
+					ResourceName:      "databricks_notebook.notebook",
+					ImportState:       true,
+					ImportStateVerify: true,
+				},
 			},
 		})
 	}
@@ -45,12 +53,7 @@ func testAccCheckDatabricksNotebookDestroy(s *terraform.State) error {
 		return errors.New("notebook still exists")
 	}
 
-	respErr, ok := err.(client.Error)
-	if !ok {
-		return err
-	}
-
-	if respErr.Code() != "RESOURCE_DOES_NOT_EXIST" {
+	if !resourceDatabricksNotebookNotExistsError(err) {
 		return err
 	}
 
@@ -80,41 +83,351 @@ resource "databricks_notebook" "notebook" {
 	)
 }
 
-func TestDatabricksNotebook_sanitizeContentFailsIfFirstLineHasWrongContent(t *testing.T) {
-	_, err := resourceDatabricksNotebookSanitizeContent(
-		databricksNotebookCreateContentFromLines([]string{
-			"wrong content",
-		}),
-	)
+func TestDatabricksNotebook_normalize(t *testing.T) {
+	cases := []struct {
+		name     string
+		language models.WorkspaceLanguage
+		source   string
+		expected string
+	}{
+		{
+			name:     "python header",
+			language: models.PYTHON,
+			source:   "# Databricks notebook source\nline1\nline2",
+			expected: "line1\nline2",
+		},
+		{
+			name:     "python cells and magic commands",
+			language: models.PYTHON,
+			source: "# Databricks notebook source\nimport os\n\n# COMMAND ----------\n\n" +
+				"# MAGIC %md\n# MAGIC # Title  \n",
+			expected: "import os\n# COMMAND ----------\n# MAGIC %md\n# MAGIC # Title",
+		},
+		{
+			name:     "scala with CRLF line endings",
+			language: models.SCALA,
+			source:   "// Databricks notebook source\r\nval x = 1\r\n\r\n// COMMAND ----------\r\nprintln(x)\r\n",
+			expected: "val x = 1\n// COMMAND ----------\nprintln(x)",
+		},
+		{
+			name:     "sql with trailing newlines",
+			language: models.SQL,
+			source:   "-- Databricks notebook source\nSELECT 1\n\n-- COMMAND ----------\n\nSELECT 2\n\n\n",
+			expected: "SELECT 1\n-- COMMAND ----------\nSELECT 2",
+		},
+		{
+			name:     "r without header",
+			language: models.R,
+			source:   "library(SparkR)\n\n# COMMAND ----------\n\nhead(df)\n",
+			expected: "library(SparkR)\n# COMMAND ----------\nhead(df)",
+		},
+		{
+			name:     "separators of other languages are kept",
+			language: models.PYTHON,
+			source:   "x = 1\n-- COMMAND ----------\n\ny = 2",
+			expected: "x = 1\n-- COMMAND ----------\n\ny = 2",
+		},
+		{
+			name:     "unknown language",
+			language: "",
+			source:   "-- Databricks notebook source\nSELECT 1\n\n-- COMMAND ----------\n\nSELECT 2",
+			expected: "SELECT 1\n-- COMMAND ----------\nSELECT 2",
+		},
+		{
+			name:     "blank lines within cells are kept",
+			language: models.PYTHON,
+			source:   "def f():\n\n    return 1\n",
+			expected: "def f():\n\n    return 1",
+		},
+	}
 
-	if err == nil {
-		t.Fatal("No error was returned when wrong content was given")
+	for _, c := range cases {
+		if normalized := resourceDatabricksNotebookNormalize(c.source, c.language); normalized != c.expected {
+			t.Fatalf("Wrong normalization for %s: %q", c.name, normalized)
+		}
+	}
+}
+
+func TestDatabricksNotebook_suppressesEquivalentContent(t *testing.T) {
+	d := resourceDatabricksNotebook().TestResourceData()
+	d.Set("language", "PYTHON")
+
+	exported := databricksNotebookCreateContentFromLines([]string{
+		"# Databricks notebook source",
+		"print('foo')",
+		"",
+		"# COMMAND ----------",
+		"",
+		"print('bar')",
+	})
+
+	local := base64.StdEncoding.EncodeToString([]byte("print('foo')\r\n# COMMAND ----------\r\nprint('bar')\r\n"))
+
+	if !resourceDatabricksNotebookSuppressEquivalentContent("content", exported, local, d) {
+		t.Fatal("Equivalent contents were not suppressed")
+	}
+
+	changed := base64.StdEncoding.EncodeToString([]byte("print('foo')\n# COMMAND ----------\nprint('baz')\n"))
+
+	if resourceDatabricksNotebookSuppressEquivalentContent("content", exported, changed, d) {
+		t.Fatal("Different contents were suppressed")
+	}
+}
+
+func TestDatabricksNotebook_handlesNonExistingNotebookError(t *testing.T) {
+	if resourceDatabricksNotebookNotExistsError(errors.New("an error")) {
+		t.Fatal("An error was incorrectly classified as non-existing-notebook error")
+	}
+
+	if !resourceDatabricksNotebookNotExistsError(client.NewError(
+		models.ErrorResponse{
+			ErrorCode: "RESOURCE_DOES_NOT_EXIST",
+			Message:   "Path (/tf-test-notebook) doesn't exist.",
+		},
+		404,
+	)) {
+		t.Fatal("A non-existing-notebook error was not detected")
+	}
+}
+
+func databricksNotebookCreateContentFromLines(lines []string) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Join(lines, "\n")))
+}
+
+func TestDatabricksNotebook_md5IgnoresHeader(t *testing.T) {
+	exported, err := resourceDatabricksNotebookMd5(databricksNotebookCreateContentFromLines([]string{
+		"# Databricks notebook source",
+		"print('foo')",
+	}), models.SOURCE, models.PYTHON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	local, err := resourceDatabricksNotebookMd5(databricksNotebookCreateContentFromLines([]string{
+		"print('foo')",
+	}), models.SOURCE, models.PYTHON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if exported != local {
+		t.Fatalf("Hashes do not match: %s != %s", exported, local)
 	}
 }
 
-func TestDatabricksNotebook_sanitizeContentStripsFirstLine(t *testing.T) {
-	content, err := resourceDatabricksNotebookSanitizeContent(
-		databricksNotebookCreateContentFromLines([]string{
-			"# Databricks notebook source",
-			"line1",
-			"line2",
-		}),
+func TestDatabricksNotebook_diffDetectsSourceChanges(t *testing.T) {
+	file, err := ioutil.TempFile("", "tf-test-notebook")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	file.WriteString("print('bar')")
+	file.Close()
+
+	oldHash, err := resourceDatabricksNotebookMd5(databricksNotebookCreateContentFromLines([]string{
+		"print('foo')",
+	}), models.SOURCE, models.PYTHON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"path":     "/tf-test-notebook",
+		"language": "PYTHON",
+		"source":   file.Name(),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	diff, err := resourceDatabricksNotebook().Diff(
+		&terraform.InstanceState{
+			ID: "/tf-test-notebook",
+			Attributes: map[string]string{
+				"path":     "/tf-test-notebook",
+				"language": "PYTHON",
+				"source":   file.Name(),
+				"md5":      oldHash,
+			},
+		},
+		terraform.NewResourceConfig(c),
+		nil,
 	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if diff == nil || diff.Attributes["md5"] == nil || diff.Attributes["md5"].New == oldHash {
+		t.Fatalf("Change of source was not detected: %v", diff)
+	}
+
+	if diff.RequiresNew() {
+		t.Fatal("Change of source should not replace the notebook")
+	}
+}
 
+func TestDatabricksNotebook_infersLanguageAndFormat(t *testing.T) {
+	cases := []struct {
+		source   string
+		language models.WorkspaceLanguage
+		format   models.WorkspaceExportFormat
+	}{
+		{"notebooks/etl.py", models.PYTHON, models.SOURCE},
+		{"notebooks/etl.scala", models.SCALA, models.SOURCE},
+		{"notebooks/etl.sql", models.SQL, models.SOURCE},
+		{"notebooks/etl.R", models.R, models.SOURCE},
+		{"notebooks/etl.ipynb", models.PYTHON, models.JUPYTER},
+		{"notebooks/etl.dbc", "", models.DBC},
+		{"notebooks/etl", "", models.SOURCE},
+	}
+
+	for _, c := range cases {
+		if language := resourceDatabricksNotebookInferLanguage("", c.source); language != c.language {
+			t.Fatalf("Wrong language for %s: %s", c.source, language)
+		}
+
+		if format := resourceDatabricksNotebookInferFormat("", c.source); format != c.format {
+			t.Fatalf("Wrong format for %s: %s", c.source, format)
+		}
+	}
+
+	if language := resourceDatabricksNotebookInferLanguage("SCALA", "notebooks/etl.py"); language != models.SCALA {
+		t.Fatalf("Configured language was not kept: %s", language)
+	}
+}
+
+func TestDatabricksNotebook_diffInfersAgainOnSourceChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-test-notebook")
 	if err != nil {
 		t.Fatalf("Unexpected error: %s", err)
 	}
+	defer os.RemoveAll(dir)
+
+	scala := filepath.Join(dir, "etl.scala")
+	ioutil.WriteFile(scala, []byte("println(\"bar\")"), 0644)
+
+	jupyter := filepath.Join(dir, "etl.ipynb")
+	ioutil.WriteFile(jupyter, []byte(`{"cells": [{"cell_type": "code", "source": "print('bar')"}]}`), 0644)
+
+	cases := []struct {
+		source   string
+		language string
+		format   string
+	}{
+		{scala, "SCALA", "SOURCE"},
+		{jupyter, "PYTHON", "JUPYTER"},
+	}
+
+	for _, c := range cases {
+		cfg, err := config.NewRawConfig(map[string]interface{}{
+			"path":   "/tf-test-notebook",
+			"source": c.source,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
 
-	decoded, err := base64.StdEncoding.DecodeString(*content)
+		diff, err := resourceDatabricksNotebook().Diff(
+			&terraform.InstanceState{
+				ID: "/tf-test-notebook",
+				Attributes: map[string]string{
+					"path":     "/tf-test-notebook",
+					"language": "PYTHON",
+					"format":   "SOURCE",
+					"source":   filepath.Join(dir, "etl.py"),
+					"md5":      "0123456789abcdef",
+				},
+			},
+			terraform.NewResourceConfig(cfg),
+			nil,
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		for key, expected := range map[string]string{"language": c.language, "format": c.format} {
+			actual := "PYTHON"
+			if key == "format" {
+				actual = "SOURCE"
+			}
+			if attr, ok := diff.Attributes[key]; ok {
+				actual = attr.New
+			}
+
+			if actual != expected {
+				t.Fatalf("Wrong %s for %s: %s", key, c.source, actual)
+			}
+		}
+	}
+}
+
+func TestDatabricksNotebook_diffKeepsConfiguredLanguage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-test-notebook")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "etl.txt")
+	ioutil.WriteFile(source, []byte("print('bar')"), 0644)
+
+	cfg, err := config.NewRawConfig(map[string]interface{}{
+		"path":     "/tf-test-notebook",
+		"language": "PYTHON",
+		"source":   source,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	diff, err := resourceDatabricksNotebook().Diff(
+		&terraform.InstanceState{
+			ID: "/tf-test-notebook",
+			Attributes: map[string]string{
+				"path":     "/tf-test-notebook",
+				"language": "PYTHON",
+				"format":   "SOURCE",
+				"source":   filepath.Join(dir, "etl.py"),
+				"md5":      "0123456789abcdef",
+			},
+		},
+		terraform.NewResourceConfig(cfg),
+		nil,
+	)
 	if err != nil {
 		t.Fatalf("Unexpected error: %s", err)
 	}
 
-	if string(decoded) != "line1\nline2" {
-		t.Fatalf("Wrong content: %s", decoded)
+	if attr, ok := diff.Attributes["language"]; ok && attr.New != "PYTHON" {
+		t.Fatalf("Configured language was not kept: %s", attr.New)
 	}
 }
 
-func databricksNotebookCreateContentFromLines(lines []string) string {
-	return base64.StdEncoding.EncodeToString([]byte(strings.Join(lines, "\n")))
+func TestDatabricksNotebook_jupyterMd5IgnoresOutputs(t *testing.T) {
+	local := `{
+		"cells": [
+			{"cell_type": "code", "source": ["import os\n", "print(os.getcwd())"], "outputs": []}
+		],
+		"metadata": {"kernelspec": {"name": "python3"}},
+		"nbformat": 4
+	}`
+
+	exported := `{"cells":[{"cell_type":"code","source":"import os\nprint(os.getcwd())",` +
+		`"outputs":[{"output_type":"stream","text":"/databricks/driver"}],"execution_count":1}],` +
+		`"metadata":{"application/vnd.databricks.v1+notebook":{"language":"python"}},"nbformat":4}`
+
+	localHash, err := resourceDatabricksNotebookMd5(base64.StdEncoding.EncodeToString([]byte(local)), models.JUPYTER, models.PYTHON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	exportedHash, err := resourceDatabricksNotebookMd5(base64.StdEncoding.EncodeToString([]byte(exported)), models.JUPYTER, models.PYTHON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if localHash != exportedHash {
+		t.Fatalf("Hashes do not match: %s != %s", localHash, exportedHash)
+	}
 }
@@ -0,0 +1,174 @@
+package databricks
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strings"
+)
+
+// secretIdSeparator joins the scope and the key (or principal) of secrets
+// and secret ACLs into a single ID. Neither scopes nor keys can contain it.
+// It also joins the cluster and the library of library IDs.
+const secretIdSeparator = "|||"
+
+func resourceDatabricksSecret() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatabricksSecretCreate,
+		Read:   resourceDatabricksSecretRead,
+		Update: resourceDatabricksSecretUpdate,
+		Delete: resourceDatabricksSecretDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"scope": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"key": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"string_value": {
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"last_updated_timestamp": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDatabricksSecretCreate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	log.Print("[DEBUG] Creating secret")
+
+	scope := d.Get("scope").(string)
+	key := d.Get("key").(string)
+
+	err := apiClient.Put(&secretsPutRequest{
+		Scope:       scope,
+		Key:         key,
+		StringValue: d.Get("string_value").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resourceDatabricksSecretBuildId(scope, key))
+
+	log.Printf("[DEBUG] Secret ID: %s", d.Id())
+
+	return resourceDatabricksSecretRead(d, m)
+}
+
+func resourceDatabricksSecretRead(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	scope, key, err := resourceDatabricksSecretParseId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	resp, err := apiClient.List(&secretsListRequest{
+		Scope: scope,
+	})
+	if err != nil {
+		if resourceDatabricksSecretNotExistsError(err) {
+			log.Printf("[WARN] Secret scope (%s) not found, removing secret from state", scope)
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	for _, secret := range resp.Secrets {
+		if secret.Key != key {
+			continue
+		}
+
+		// Secret values cannot be read back, so a change in the timestamp
+		// is the only sign that the value was modified outside Terraform.
+		// Clearing the value makes the next plan put it again.
+		if timestamp, ok := d.GetOk("last_updated_timestamp"); ok && int64(timestamp.(int)) != secret.LastUpdatedTimestamp {
+			log.Printf("[WARN] Secret (%s) was modified outside of Terraform", d.Id())
+			d.Set("string_value", "")
+		}
+
+		d.Set("scope", scope)
+		d.Set("key", key)
+		d.Set("last_updated_timestamp", secret.LastUpdatedTimestamp)
+
+		return nil
+	}
+
+	log.Printf("[WARN] Secret (%s) not found, removing from state", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksSecretUpdate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	log.Printf("[DEBUG] Updating secret: %s", d.Id())
+
+	err := apiClient.Put(&secretsPutRequest{
+		Scope:       d.Get("scope").(string),
+		Key:         d.Get("key").(string),
+		StringValue: d.Get("string_value").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The put changes the timestamp, which must not be taken as a change
+	// made outside Terraform.
+	d.Set("last_updated_timestamp", 0)
+
+	return resourceDatabricksSecretRead(d, m)
+}
+
+func resourceDatabricksSecretDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	log.Printf("[DEBUG] Deleting secret: %s", d.Id())
+
+	scope, key, err := resourceDatabricksSecretParseId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = apiClient.Delete(&secretsDeleteRequest{
+		Scope: scope,
+		Key:   key,
+	})
+	if err != nil && !resourceDatabricksSecretNotExistsError(err) {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksSecretBuildId(scope string, name string) string {
+	return scope + secretIdSeparator + name
+}
+
+func resourceDatabricksSecretParseId(id string) (string, string, error) {
+	parts := strings.Split(id, secretIdSeparator)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid ID (%s): expected scope%sname", id, secretIdSeparator)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,124 @@
+package databricks
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+func resourceDatabricksSecretAcl() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatabricksSecretAclCreate,
+		Read:   resourceDatabricksSecretAclRead,
+		Update: resourceDatabricksSecretAclUpdate,
+		Delete: resourceDatabricksSecretAclDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"scope": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"principal": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"permission": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateStringInSlice([]string{"READ", "WRITE", "MANAGE"}),
+			},
+		},
+	}
+}
+
+func resourceDatabricksSecretAclCreate(d *schema.ResourceData, m interface{}) error {
+	log.Print("[DEBUG] Creating secret ACL")
+
+	err := resourceDatabricksSecretAclPut(d, m)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resourceDatabricksSecretBuildId(d.Get("scope").(string), d.Get("principal").(string)))
+
+	log.Printf("[DEBUG] Secret ACL ID: %s", d.Id())
+
+	return resourceDatabricksSecretAclRead(d, m)
+}
+
+func resourceDatabricksSecretAclRead(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	scope, principal, err := resourceDatabricksSecretParseId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	resp, err := apiClient.GetAcl(&secretsAclRequest{
+		Scope:     scope,
+		Principal: principal,
+	})
+	if err != nil {
+		if resourceDatabricksSecretNotExistsError(err) {
+			log.Printf("[WARN] Secret ACL (%s) not found, removing from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	d.Set("scope", scope)
+	d.Set("principal", resp.Principal)
+	d.Set("permission", resp.Permission)
+
+	return nil
+}
+
+func resourceDatabricksSecretAclUpdate(d *schema.ResourceData, m interface{}) error {
+	log.Printf("[DEBUG] Updating secret ACL: %s", d.Id())
+
+	err := resourceDatabricksSecretAclPut(d, m)
+	if err != nil {
+		return err
+	}
+
+	return resourceDatabricksSecretAclRead(d, m)
+}
+
+func resourceDatabricksSecretAclDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	log.Printf("[DEBUG] Deleting secret ACL: %s", d.Id())
+
+	scope, principal, err := resourceDatabricksSecretParseId(d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = apiClient.DeleteAcl(&secretsAclRequest{
+		Scope:     scope,
+		Principal: principal,
+	})
+	if err != nil && !resourceDatabricksSecretNotExistsError(err) {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksSecretAclPut(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	return apiClient.PutAcl(&secretsPutAclRequest{
+		Scope:      d.Get("scope").(string),
+		Principal:  d.Get("principal").(string),
+		Permission: d.Get("permission").(string),
+	})
+}
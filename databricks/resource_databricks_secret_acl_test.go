@@ -0,0 +1,106 @@
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"testing"
+)
+
+func TestAccDatabricksSecretAcl_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksSecretAclDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksSecretAclConfig("READ"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksSecretAclExists("databricks_secret_acl.acl"),
+					resource.TestCheckResourceAttr(
+						"databricks_secret_acl.acl", "permission", "READ"),
+				),
+			},
+			{
+				Config: testAccDatabricksSecretAclConfig("WRITE"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksSecretAclExists("databricks_secret_acl.acl"),
+					resource.TestCheckResourceAttr(
+						"databricks_secret_acl.acl", "permission", "WRITE"),
+				),
+			},
+			{
+				ResourceName:      "databricks_secret_acl.acl",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksSecretAclExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		scope, principal, err := resourceDatabricksSecretParseId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		conn := testAccProvider.Meta().(*Client).secrets
+
+		_, err = conn.GetAcl(&secretsAclRequest{
+			Scope:     scope,
+			Principal: principal,
+		})
+
+		return err
+	}
+}
+
+func testAccCheckDatabricksSecretAclDestroy(s *terraform.State) error {
+	endpoint := testAccProvider.Meta().(*Client).secrets
+
+	scope, principal, err := resourceDatabricksSecretParseId(s.RootModule().Resources["databricks_secret_acl.acl"].Primary.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = endpoint.GetAcl(&secretsAclRequest{
+		Scope:     scope,
+		Principal: principal,
+	})
+
+	if err == nil {
+		return errors.New("secret ACL still exists")
+	}
+
+	if !resourceDatabricksSecretNotExistsError(err) {
+		return err
+	}
+
+	return nil
+}
+
+func testAccDatabricksSecretAclConfig(permission string) string {
+	const formatStr = `
+resource "databricks_secret_scope" "scope" {
+	name = "tf-test-scope"
+}
+
+resource "databricks_secret_acl" "acl" {
+	scope      = "${databricks_secret_scope.scope.name}"
+	principal  = "users"
+	permission = "%s"
+}
+`
+	return fmt.Sprintf(formatStr, permission)
+}
@@ -0,0 +1,114 @@
+package databricks
+
+import (
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+func resourceDatabricksSecretScope() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDatabricksSecretScopeCreate,
+		Read:   resourceDatabricksSecretScopeRead,
+		Delete: resourceDatabricksSecretScopeDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// The principal cannot be read back, so it is missing from the
+			// state of imported scopes.
+			"initial_manage_principal": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: resourceDatabricksSecretScopeSuppressImportedPrincipal,
+			},
+			"backend_type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDatabricksSecretScopeCreate(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	log.Print("[DEBUG] Creating secret scope")
+
+	name := d.Get("name").(string)
+
+	err := apiClient.CreateScope(&secretsCreateScopeRequest{
+		Scope:                  name,
+		InitialManagePrincipal: d.Get("initial_manage_principal").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(name)
+
+	log.Printf("[DEBUG] Secret scope ID: %s", d.Id())
+
+	return resourceDatabricksSecretScopeRead(d, m)
+}
+
+func resourceDatabricksSecretScopeRead(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	resp, err := apiClient.ListScopes()
+	if err != nil {
+		return err
+	}
+
+	for _, scope := range resp.Scopes {
+		if scope.Name == d.Id() {
+			d.Set("name", scope.Name)
+			d.Set("backend_type", scope.BackendType)
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] Secret scope (%s) not found, removing from state", d.Id())
+	d.SetId("")
+
+	return nil
+}
+
+func resourceDatabricksSecretScopeDelete(d *schema.ResourceData, m interface{}) error {
+	apiClient := m.(*Client).secrets
+
+	log.Printf("[DEBUG] Deleting secret scope: %s", d.Id())
+
+	// Deleting a scope deletes the secrets and ACLs within it, so it does not
+	// matter whether they were already removed.
+	err := apiClient.DeleteScope(&secretsDeleteScopeRequest{
+		Scope: d.Id(),
+	})
+	if err != nil && !resourceDatabricksSecretNotExistsError(err) {
+		return err
+	}
+
+	d.SetId("")
+
+	return nil
+}
+
+// resourceDatabricksSecretScopeSuppressImportedPrincipal ignores the principal
+// of existing scopes that have none in the state, so that importing a scope
+// does not replace it (deleting all its secrets).
+func resourceDatabricksSecretScopeSuppressImportedPrincipal(k, old, new string, d *schema.ResourceData) bool {
+	return old == "" && d.Id() != ""
+}
+
+func resourceDatabricksSecretNotExistsError(err error) bool {
+	databricksError, ok := err.(client.Error)
+	return ok && databricksError.Code() == "RESOURCE_DOES_NOT_EXIST"
+}
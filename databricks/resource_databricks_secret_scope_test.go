@@ -0,0 +1,157 @@
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"github.com/betabandido/databricks-sdk-go/client"
+	"github.com/betabandido/databricks-sdk-go/models"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"testing"
+)
+
+func TestAccDatabricksSecretScope_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksSecretScopeDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksSecretScopeConfig(),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksSecretScopeExists("databricks_secret_scope.scope"),
+					resource.TestCheckResourceAttr(
+						"databricks_secret_scope.scope", "name", "tf-test-scope"),
+					resource.TestCheckResourceAttr(
+						"databricks_secret_scope.scope", "backend_type", "DATABRICKS"),
+				),
+			},
+			{
+				ResourceName:            "databricks_secret_scope.scope",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initial_manage_principal"},
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksSecretScopeExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		exists, err := testAccDatabricksSecretScopeExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("secret scope %s does not exist", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckDatabricksSecretScopeDestroy(s *terraform.State) error {
+	name := s.RootModule().Resources["databricks_secret_scope.scope"].Primary.ID
+
+	exists, err := testAccDatabricksSecretScopeExists(name)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return errors.New("secret scope still exists")
+	}
+
+	return nil
+}
+
+func testAccDatabricksSecretScopeExists(name string) (bool, error) {
+	endpoint := testAccProvider.Meta().(*Client).secrets
+
+	resp, err := endpoint.ListScopes()
+	if err != nil {
+		return false, err
+	}
+
+	for _, scope := range resp.Scopes {
+		if scope.Name == name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func testAccDatabricksSecretScopeConfig() string {
+	return `
+resource "databricks_secret_scope" "scope" {
+	name                     = "tf-test-scope"
+	initial_manage_principal = "users"
+}
+`
+}
+
+func TestDatabricksSecretScope_handlesNonExistingScopeError(t *testing.T) {
+	if resourceDatabricksSecretNotExistsError(errors.New("an error")) {
+		t.Fatal("An error was incorrectly classified as non-existing-scope error")
+	}
+
+	if !resourceDatabricksSecretNotExistsError(client.NewError(
+		models.ErrorResponse{
+			ErrorCode: "RESOURCE_DOES_NOT_EXIST",
+			Message:   "Scope tf-test-scope does not exist!",
+		},
+		404,
+	)) {
+		t.Fatal("A non-existing-scope error was not detected")
+	}
+}
+
+func TestDatabricksSecretScope_importedScopeIsNotReplaced(t *testing.T) {
+	c, err := config.NewRawConfig(map[string]interface{}{
+		"name":                     "tf-test-scope",
+		"initial_manage_principal": "users",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	diff, err := resourceDatabricksSecretScope().Diff(
+		&terraform.InstanceState{
+			ID: "tf-test-scope",
+			Attributes: map[string]string{
+				"name":         "tf-test-scope",
+				"backend_type": "DATABRICKS",
+			},
+		},
+		terraform.NewResourceConfig(c),
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if diff != nil && diff.RequiresNew() {
+		t.Fatalf("Imported scope would be replaced: %v", diff)
+	}
+
+	diff, err = resourceDatabricksSecretScope().Diff(nil, terraform.NewResourceConfig(c), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if diff.Attributes["initial_manage_principal"] == nil || diff.Attributes["initial_manage_principal"].New != "users" {
+		t.Fatalf("Principal of new scope is missing: %v", diff)
+	}
+}
@@ -0,0 +1,186 @@
+package databricks
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"testing"
+)
+
+func TestAccDatabricksSecret_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDatabricksSecretDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDatabricksSecretConfig("secret"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksSecretExists("databricks_secret.secret"),
+					resource.TestCheckResourceAttr(
+						"databricks_secret.secret", "id", "tf-test-scope|||password"),
+					resource.TestCheckResourceAttrSet(
+						"databricks_secret.secret", "last_updated_timestamp"),
+				),
+			},
+			{
+				Config: testAccDatabricksSecretConfig("another secret"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckDatabricksSecretExists("databricks_secret.secret"),
+					resource.TestCheckResourceAttr(
+						"databricks_secret.secret", "string_value", "another secret"),
+				),
+			},
+			{
+				ResourceName:            "databricks_secret.secret",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"string_value"},
+			},
+		},
+	})
+}
+
+func testAccCheckDatabricksSecretExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no ID is set")
+		}
+
+		exists, err := testAccDatabricksSecretExists(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return fmt.Errorf("secret %s does not exist", rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testAccCheckDatabricksSecretDestroy(s *terraform.State) error {
+	exists, err := testAccDatabricksSecretExists(s.RootModule().Resources["databricks_secret.secret"].Primary.ID)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return errors.New("secret still exists")
+	}
+
+	return nil
+}
+
+func testAccDatabricksSecretExists(id string) (bool, error) {
+	endpoint := testAccProvider.Meta().(*Client).secrets
+
+	scope, key, err := resourceDatabricksSecretParseId(id)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := endpoint.List(&secretsListRequest{
+		Scope: scope,
+	})
+	if err != nil {
+		if resourceDatabricksSecretNotExistsError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, secret := range resp.Secrets {
+		if secret.Key == key {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func testAccDatabricksSecretConfig(value string) string {
+	const formatStr = `
+resource "databricks_secret_scope" "scope" {
+	name = "tf-test-scope"
+}
+
+resource "databricks_secret" "secret" {
+	scope        = "${databricks_secret_scope.scope.name}"
+	key          = "password"
+	string_value = "%s"
+}
+`
+	return fmt.Sprintf(formatStr, value)
+}
+
+func TestDatabricksSecret_parseId(t *testing.T) {
+	scope, key, err := resourceDatabricksSecretParseId(resourceDatabricksSecretBuildId("scope", "key"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if scope != "scope" || key != "key" {
+		t.Fatalf("Wrong scope (%s) or key (%s)", scope, key)
+	}
+}
+
+func TestDatabricksSecret_parseIdFailsWithWrongFormat(t *testing.T) {
+	ids := []string{
+		"scope",
+		"scope|||",
+		"|||key",
+		"scope|||key|||extra",
+	}
+
+	for _, id := range ids {
+		if _, _, err := resourceDatabricksSecretParseId(id); err == nil {
+			t.Fatalf("No error was returned for ID %s", id)
+		}
+	}
+}
+
+func TestDatabricksSecret_updateKeepsValue(t *testing.T) {
+	handler := func(path string, body []byte) interface{} {
+		if path == "secrets/list" {
+			return secretsListResponse{
+				Secrets: []secretMetadata{
+					{Key: "password", LastUpdatedTimestamp: 1533818263591},
+				},
+			}
+		}
+		return nil
+	}
+
+	cl, closeServer := testDatabricksServer(t, handler)
+	defer closeServer()
+
+	d := schema.TestResourceDataRaw(t, resourceDatabricksSecret().Schema, map[string]interface{}{
+		"scope":        "tf-test",
+		"key":          "password",
+		"string_value": "another secret",
+	})
+	d.SetId("tf-test|||password")
+	d.Set("last_updated_timestamp", 1533818000000)
+
+	err := resourceDatabricksSecretUpdate(d, &Client{secrets: &secretsEndpoint{Client: cl}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if d.Get("string_value").(string) != "another secret" {
+		t.Fatalf("Value was not kept: %q", d.Get("string_value"))
+	}
+
+	if d.Get("last_updated_timestamp").(int) != 1533818263591 {
+		t.Fatalf("Wrong timestamp: %d", d.Get("last_updated_timestamp"))
+	}
+}
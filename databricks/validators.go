@@ -0,0 +1,41 @@
+package databricks
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"strings"
+)
+
+func validateStringInSlice(valid []string) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (s []string, es []error) {
+		v, ok := i.(string)
+		if !ok {
+			es = append(es, fmt.Errorf("expected type of %s to be string", k))
+			return
+		}
+
+		for _, str := range valid {
+			if v == str {
+				return
+			}
+		}
+
+		es = append(es, fmt.Errorf("expected %s to be one of [%s], got %s", k, strings.Join(valid, ", "), v))
+		return
+	}
+}
+
+func validateJson(i interface{}, k string) (s []string, es []error) {
+	v, ok := i.(string)
+	if !ok {
+		es = append(es, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+
+	if !json.Valid([]byte(v)) {
+		es = append(es, fmt.Errorf("expected %s to be a valid JSON document", k))
+	}
+
+	return
+}